@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"cinema-booking/config"
 	"cinema-booking/internal/auth"
@@ -13,8 +14,12 @@ import (
 	"cinema-booking/internal/middleware"
 	"cinema-booking/internal/model"
 	"cinema-booking/internal/mq"
+	"cinema-booking/internal/quota"
 	"cinema-booking/internal/repository"
+	"cinema-booking/internal/seatmap"
 	"cinema-booking/internal/seed"
+	"cinema-booking/internal/waitlist"
+	"cinema-booking/internal/waitroom"
 	"cinema-booking/internal/ws"
 	"cinema-booking/internal/worker"
 	"github.com/gin-gonic/gin"
@@ -42,6 +47,9 @@ func main() {
 
 	lockMgr := lock.NewManager(rdb, cfg.LockTTLSeconds)
 	repo := repository.NewMongoRepo(db)
+	if err := repo.Migrate(ctx); err != nil {
+		log.Fatal("migrate:", err)
+	}
 
 	seed.Run(ctx, repo)
 
@@ -54,6 +62,9 @@ func main() {
 			log.Printf("audit insert: %v", err)
 		}
 	}
+	seatVersions := seatmap.NewVersions(rdb, hub)
+	seatQuota := quota.NewLimiter(rdb, cfg.SeatQuotaPerUser)
+	waitlistSvc := waitlist.NewService(rdb, repo, lockMgr, hub, seatVersions, seatQuota, cfg.WaitlistOfferSeconds, time.Duration(cfg.SalesCutoffMinutes)*time.Minute, onAudit)
 	sub := mq.NewSubscriber(rdb, func(ev mq.Event) {
 		onAudit(ev.Type, ev.Payload)
 		if ev.Type == "BOOKING_SUCCESS" {
@@ -62,24 +73,62 @@ func main() {
 				hub.BroadcastNotification("screening:"+sid, ev.Type, ev.Payload)
 			}
 		}
-		if ev.Type == "SEAT_RELEASED" {
+		if ev.Type == "SEAT_RELEASED" || ev.Type == "BOOKING_REFUNDED" {
 			if sid, ok := ev.Payload["screening_id"].(string); ok {
 				hub.BroadcastNotification("screening:"+sid, ev.Type, ev.Payload)
+				// JSON numbers decode as float64
+				row, rowOK := ev.Payload["seat_row"].(float64)
+				col, colOK := ev.Payload["seat_col"].(float64)
+				if rowOK && colOK {
+					go waitlistSvc.OnSeatReleased(ctx, sid, int(row), int(col))
+				}
 			}
 		}
 	})
 	go sub.Run(ctx)
 
-	go worker.RunLockExpiry(ctx, repo, lockMgr, pub, hub, onAudit)
+	expiry := worker.NewLockExpiry(repo, lockMgr, pub, hub, seatVersions, onAudit, cfg.LockTTLSeconds)
+	go expiry.RunEvents(ctx)
+	go expiry.RunSweep(ctx, time.Duration(cfg.LockSweepIntervalSeconds)*time.Second)
+	reconciler := worker.NewReconciler(repo, lockMgr, pub, hub, seatVersions, onAudit, cfg.LockTTLSeconds)
+	go reconciler.Run(ctx, time.Duration(cfg.ReconcileIntervalSeconds)*time.Second)
+
+	room := waitroom.NewRoom(rdb, hub, cfg.WaitingRoomCapacity, cfg.WaitingRoomAdmitSeconds)
+	withRoom, err := repo.FindScreenings(ctx, map[string]interface{}{"waiting_room": true})
+	if err != nil {
+		log.Fatal("waiting rooms:", err)
+	}
+	roomIDs := make([]string, len(withRoom))
+	for i, s := range withRoom {
+		roomIDs[i] = s.ID.Hex()
+	}
+	if err := room.SyncEnabled(ctx, roomIDs); err != nil {
+		log.Fatal("waiting rooms:", err)
+	}
+	go room.Run(ctx, 2*time.Second)
 
 	h := &handler.Handler{
-		Repo:           repo,
-		Lock:           lockMgr,
-		Hub:            hub,
-		Pub:            pub,
-		JWTSecret:      cfg.JWTSecret,
-		LockTTLSeconds: cfg.LockTTLSeconds,
-		OnAudit:        onAudit,
+		Repo:              repo,
+		Lock:              lockMgr,
+		Hub:               hub,
+		Seats:             seatVersions,
+		Pub:               pub,
+		JWTSecret:         cfg.JWTSecret,
+		LockTTLSeconds:    cfg.LockTTLSeconds,
+		LockMaxExtensions: cfg.LockMaxExtensions,
+		Quota:             seatQuota,
+		Waitlist:          waitlistSvc,
+		Room:              room,
+		GroupHoldTTL:      time.Duration(cfg.GroupHoldTTLSeconds) * time.Second,
+		SalesCutoff:       time.Duration(cfg.SalesCutoffMinutes) * time.Minute,
+		CleaningBuffer:    time.Duration(cfg.CleaningBufferMinutes) * time.Minute,
+		TransferOfferTTL:  time.Duration(cfg.TransferOfferHours) * time.Hour,
+		Reconciler:        reconciler,
+		Refund: handler.RefundPolicy{
+			FullRefundBefore: time.Duration(cfg.RefundFullHours) * time.Hour,
+			PartialPercent:   cfg.RefundPartialPercent,
+		},
+		OnAudit: onAudit,
 	}
 
 	r := gin.Default()
@@ -95,16 +144,44 @@ func main() {
 	r.POST("/auth/login", h.Login)
 	r.POST("/auth/register", h.Register)
 
+	idem := middleware.Idempotency(rdb, time.Duration(cfg.IdempotencyTTLSeconds)*time.Second)
+	admitted := h.RequireAdmission() // after idem, so a retried request replays its stored response even once the pass has lapsed
+
 	api := r.Group("/api")
 	api.Use(middleware.Auth(cfg.JWTSecret))
 	{
+		api.GET("/movies", h.ListNowShowing)
+		api.GET("/movies/:id", h.GetMovieShowtimes)
 		api.GET("/screenings", h.ListScreenings)
 		api.GET("/screenings/:id", h.GetScreening)
-		api.GET("/screenings/:id/seats", h.GetSeatMap)
-		api.GET("/screenings/:id/seat-details", h.GetSeatDetails)
+		api.GET("/screenings/:id/seats", admitted, h.GetSeatMap)
+		api.GET("/screenings/:id/seats/changes", admitted, h.GetSeatChanges)
+		api.GET("/screenings/:id/seat-details", admitted, h.GetSeatDetails)
 		api.GET("/screenings/:id/ws", h.ServeWS)
-		api.POST("/screenings/:id/lock", h.LockSeat)
-		api.POST("/bookings/confirm", h.ConfirmPayment)
+		api.GET("/screenings/:id/queue", h.QueueStatus)
+		api.POST("/screenings/:id/queue", h.JoinQueue)
+		api.DELETE("/screenings/:id/queue", h.LeaveQueue)
+		api.GET("/screenings/:id/best-seats", admitted, h.FindBestSeats)
+		api.POST("/screenings/:id/best-seats/lock", idem, admitted, h.LockBestSeats)
+		api.POST("/screenings/:id/lock", idem, admitted, h.LockSeat)
+		api.GET("/screenings/:id/waitlist", h.WaitlistPosition)
+		api.POST("/screenings/:id/waitlist", h.JoinWaitlist)
+		api.DELETE("/screenings/:id/waitlist", h.LeaveWaitlist)
+		api.POST("/screenings/:id/groups", idem, admitted, h.CreateGroupHold)
+		api.GET("/groups/:id", h.GetGroupHold)
+		api.POST("/groups/:id/claim", idem, h.ClaimGroupSeat)
+		api.GET("/me/bookings", h.ListMyBookings)
+		api.GET("/me/transfers", h.ListIncomingTransfers)
+		api.POST("/bookings/confirm", idem, h.ConfirmPayment)
+		api.GET("/orders/:id", h.GetOrder)
+		api.POST("/orders/:id/confirm", idem, h.ConfirmOrder)
+		api.POST("/orders/:id/cancel", idem, h.CancelOrder)
+		api.POST("/bookings/:id/extend", idem, h.ExtendLock)
+		api.POST("/bookings/:id/cancel", idem, h.CancelBooking)
+		api.POST("/bookings/:id/exchange", idem, h.ExchangeSeat)
+		api.POST("/bookings/:id/transfer", idem, h.OfferTransfer)
+		api.DELETE("/bookings/:id/transfer", h.RevokeTransfer)
+		api.POST("/bookings/:id/transfer/accept", idem, h.AcceptTransfer)
 	}
 
 	admin := r.Group("/admin")
@@ -113,6 +190,8 @@ func main() {
 		admin.GET("/bookings", h.ListBookingsAdmin)
 		admin.GET("/audit-logs", h.ListAuditLogs)
 		admin.GET("/ws", h.ServeAdminWS)
+		admin.GET("/reconcile", h.ReconcileSummary)
+		admin.POST("/reconcile", h.RunReconcile)
 	}
 
 	r.POST("/admin/login", func(c *gin.Context) {
@@ -145,7 +224,30 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"token": token, "user_id": u.ID, "role": "ADMIN"})
 	})
 
+	admin.GET("/layouts", h.ListLayouts)
+	admin.GET("/layouts/:id", h.GetLayout)
+	admin.POST("/layouts", h.CreateLayout)
+	admin.DELETE("/layouts/:id", h.DeleteLayout)
+	admin.GET("/movies", h.ListMovies)
+	admin.GET("/movies/:id", h.GetMovie)
+	admin.POST("/movies", h.CreateMovie)
+	admin.PUT("/movies/:id", h.UpdateMovie)
+	admin.DELETE("/movies/:id", h.DeleteMovie)
+	admin.GET("/cinemas", h.ListCinemas)
+	admin.GET("/cinemas/:id", h.GetCinema)
+	admin.POST("/cinemas", h.CreateCinema)
+	admin.PUT("/cinemas/:id", h.UpdateCinema)
+	admin.DELETE("/cinemas/:id", h.DeleteCinema)
+	admin.GET("/halls", h.ListHalls)
+	admin.GET("/halls/:id", h.GetHall)
+	admin.POST("/halls", h.CreateHall)
+	admin.PUT("/halls/:id", h.UpdateHall)
+	admin.DELETE("/halls/:id", h.DeleteHall)
 	admin.POST("/screenings", h.CreateScreening)
+	admin.PUT("/screenings/:id", h.UpdateScreening)
+	admin.POST("/screenings/:id/pause", h.PauseSales)
+	admin.POST("/screenings/:id/resume", h.ResumeSales)
+	admin.POST("/screenings/:id/waiting-room", h.SetWaitingRoom)
 
 	addr := ":" + strconv.Itoa(cfg.ServerPort)
 	if err := r.Run(addr); err != nil && err != http.ErrServerClosed {
@@ -157,7 +259,8 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, X-Admission-Token, If-None-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag, Idempotent-Replayed")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -11,20 +11,86 @@ type Config struct {
 	RedisAddr      string
 	JWTSecret      string
 	LockTTLSeconds int
+	// LockMaxExtensions caps how many times a PENDING booking's lock may be extended.
+	LockMaxExtensions int
+	// RefundFullHours: confirmed bookings cancelled at least this many hours before the screening get a full refund.
+	RefundFullHours int
+	// RefundPartialPercent is refunded for cancellations after that point but before the screening starts.
+	RefundPartialPercent int
+	// SeatQuotaPerUser caps held + booked seats per user per screening (0 disables).
+	SeatQuotaPerUser int
+	// IdempotencyTTLSeconds is how long responses stored for an Idempotency-Key are replayed.
+	IdempotencyTTLSeconds int
+	// WaitlistOfferSeconds is how long a waitlisted user holds an offered seat before it rolls to the next user.
+	WaitlistOfferSeconds int
+	// GroupHoldTTLSeconds is how long a group hold keeps its seats for members to claim and pay.
+	GroupHoldTTLSeconds int
+	// SalesCutoffMinutes closes sales this many minutes before a screening starts.
+	SalesCutoffMinutes int
+	// ReconcileIntervalSeconds is how often Redis locks and PENDING bookings are reconciled.
+	ReconcileIntervalSeconds int
+	// LockSweepIntervalSeconds is the safety-net polling interval behind Redis expired-key events.
+	LockSweepIntervalSeconds int
+	// WaitingRoomCapacity is how many admitted users per screening may browse seats and lock at once.
+	WaitingRoomCapacity int
+	// WaitingRoomAdmitSeconds is how long an admission token stays valid.
+	WaitingRoomAdmitSeconds int
+	// CleaningBufferMinutes is kept free after each screening in a hall unless the hall sets its own.
+	CleaningBufferMinutes int
+	// TransferOfferHours is how long a ticket transfer offer can be accepted.
+	TransferOfferHours int
 }
 
 func Load() *Config {
 	port, _ := strconv.Atoi(getEnv("PORT", "8080"))
 	lockTTL, _ := strconv.Atoi(getEnv("LOCK_TTL_SECONDS", "300"))
+	maxExt, _ := strconv.Atoi(getEnv("LOCK_MAX_EXTENSIONS", "2"))
+	refundHours, _ := strconv.Atoi(getEnv("REFUND_FULL_HOURS", "24"))
+	refundPartial, _ := strconv.Atoi(getEnv("REFUND_PARTIAL_PERCENT", "50"))
+	seatQuota, _ := strconv.Atoi(getEnv("SEAT_QUOTA_PER_USER", "10"))
+	idemTTL, _ := strconv.Atoi(getEnv("IDEMPOTENCY_TTL_SECONDS", "86400"))
+	offerTTL, _ := strconv.Atoi(getEnv("WAITLIST_OFFER_SECONDS", "120"))
+	groupTTL, _ := strconv.Atoi(getEnv("GROUP_HOLD_TTL_SECONDS", "1800"))
+	salesCutoff, _ := strconv.Atoi(getEnv("SALES_CUTOFF_MINUTES", "15"))
+	reconcileEvery := getEnvPositive("RECONCILE_INTERVAL_SECONDS", 60)
+	sweepEvery := getEnvPositive("LOCK_SWEEP_INTERVAL_SECONDS", 120)
+	roomCapacity, _ := strconv.Atoi(getEnv("WAITING_ROOM_CAPACITY", "100"))
+	admitTTL, _ := strconv.Atoi(getEnv("WAITING_ROOM_ADMIT_SECONDS", "600"))
+	cleaning, _ := strconv.Atoi(getEnv("CLEANING_BUFFER_MINUTES", "15"))
+	transferHours, _ := strconv.Atoi(getEnv("TRANSFER_OFFER_HOURS", "48"))
 	return &Config{
-		ServerPort:     port,
-		MongoURI:       getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-		RedisAddr:      getEnv("REDIS_ADDR", "localhost:6379"),
-		JWTSecret:      getEnv("JWT_SECRET", "dev-secret"),
-		LockTTLSeconds: lockTTL,
+		ServerPort:               port,
+		MongoURI:                 getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+		RedisAddr:                getEnv("REDIS_ADDR", "localhost:6379"),
+		JWTSecret:                getEnv("JWT_SECRET", "dev-secret"),
+		LockTTLSeconds:           lockTTL,
+		LockMaxExtensions:        maxExt,
+		RefundFullHours:          refundHours,
+		RefundPartialPercent:     refundPartial,
+		SeatQuotaPerUser:         seatQuota,
+		IdempotencyTTLSeconds:    idemTTL,
+		WaitlistOfferSeconds:     offerTTL,
+		GroupHoldTTLSeconds:      groupTTL,
+		SalesCutoffMinutes:       salesCutoff,
+		ReconcileIntervalSeconds: reconcileEvery,
+		LockSweepIntervalSeconds: sweepEvery,
+		WaitingRoomCapacity:      roomCapacity,
+		WaitingRoomAdmitSeconds:  admitTTL,
+		CleaningBufferMinutes:    cleaning,
+		TransferOfferHours:       transferHours,
 	}
 }
 
+// getEnvPositive reads a positive integer, falling back when the variable is unset, unparsable or not above zero.
+// Used for intervals, which a ticker cannot run with at zero.
+func getEnvPositive(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
 func getEnv(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
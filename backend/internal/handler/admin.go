@@ -2,10 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -17,32 +19,15 @@ func (h *Handler) ListBookingsAdmin(c *gin.Context) {
 	if screeningID := c.Query("screening_id"); screeningID != "" {
 		filter["screening_id"] = screeningID
 	} else if movieName := strings.TrimSpace(c.Query("movie_name")); movieName != "" {
-		screenings, _ := h.Repo.ListScreenings(c.Request.Context())
-		var ids []string
-		lower := strings.ToLower(movieName)
-		for _, s := range screenings {
-			if strings.Contains(strings.ToLower(s.MovieName), lower) {
-				ids = append(ids, s.ID.Hex())
-			}
-		}
-		if len(ids) > 0 {
-			filter["screening_id"] = bson.M{"$in": ids}
-		} else {
-			filter["screening_id"] = "none"
+		// Match catalog titles, then take every screening of those movies
+		movies, _ := h.Repo.ListMovies(c.Request.Context(), bson.M{"title": primitive.Regex{Pattern: regexp.QuoteMeta(movieName), Options: "i"}})
+		movieIDs := []string{}
+		for _, m := range movies {
+			movieIDs = append(movieIDs, m.ID.Hex())
 		}
+		filter["screening_id"] = bson.M{"$in": h.screeningIDs(c, bson.M{"movie_id": bson.M{"$in": movieIDs}})}
 	} else if movieID := c.Query("movie_id"); movieID != "" {
-		screenings, _ := h.Repo.ListScreenings(c.Request.Context())
-		var ids []string
-		for _, s := range screenings {
-			if s.MovieID == movieID {
-				ids = append(ids, s.ID.Hex())
-			}
-		}
-		if len(ids) > 0 {
-			filter["screening_id"] = bson.M{"$in": ids}
-		} else {
-			filter["screening_id"] = "none"
-		}
+		filter["screening_id"] = bson.M{"$in": h.screeningIDs(c, bson.M{"movie_id": movieID})}
 	}
 	list, err := h.Repo.ListBookings(c.Request.Context(), filter)
 	if err != nil {
@@ -64,6 +49,16 @@ func (h *Handler) ListBookingsAdmin(c *gin.Context) {
 	c.JSON(http.StatusOK, out)
 }
 
+// screeningIDs returns the IDs of the screenings matching filter (never nil, so it is safe in $in).
+func (h *Handler) screeningIDs(c *gin.Context, filter bson.M) []string {
+	screenings, _ := h.Repo.FindScreenings(c.Request.Context(), filter)
+	ids := []string{}
+	for _, s := range screenings {
+		ids = append(ids, s.ID.Hex())
+	}
+	return ids
+}
+
 func (h *Handler) ListAuditLogs(c *gin.Context) {
 	// Simple list from MongoDB; could add pagination
 	col := h.Repo.AuditCol()
@@ -80,3 +75,13 @@ func (h *Handler) ListAuditLogs(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, logs)
 }
+
+// ReconcileSummary returns the last Redis/Mongo reconciliation pass so operators can see drift.
+func (h *Handler) ReconcileSummary(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Reconciler.Last())
+}
+
+// RunReconcile runs a reconciliation pass now and returns its summary.
+func (h *Handler) RunReconcile(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Reconciler.RunOnce(c.Request.Context()))
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/seating"
+	"github.com/gin-gonic/gin"
+)
+
+const maxPartySize = 10
+
+// BestSeatsRequest is used by FindBestSeats (query) and LockBestSeats (JSON body).
+type BestSeatsRequest struct {
+	PartySize int                `form:"party_size" json:"party_size"`
+	Prefer    seating.Preference `form:"prefer" json:"prefer"`
+	Limit     int                `form:"limit" json:"limit"`
+}
+
+func (r *BestSeatsRequest) normalize() bool {
+	if r.PartySize < 1 || r.PartySize > maxPartySize {
+		return false
+	}
+	switch r.Prefer {
+	case seating.PreferCenter, seating.PreferBack, seating.PreferFront:
+	default:
+		r.Prefer = seating.PreferCenter
+	}
+	if r.Limit <= 0 {
+		r.Limit = 5
+	}
+	return true
+}
+
+// bestBlocks ranks blocks for a party on the live seat map of s. Under a REJECT orphan rule blocks that would
+// strand a seat are left out, so the lock that follows is not refused; under WARN they rank last.
+func (h *Handler) bestBlocks(ctx context.Context, s *model.Screening, size int, pref seating.Preference, limit int) []seating.Block {
+	grid := h.seatGrid(ctx, s)
+	rule := s.SeatingRule
+	if rule == nil {
+		return seating.BestBlocks(grid, size, pref, limit, nil)
+	}
+	if rule.OrphanSeat != model.OrphanRuleWarn && rule.OrphanSeat != model.OrphanRuleReject {
+		return seating.BestBlocks(grid, size, pref, limit, rule.AisleAfter)
+	}
+	blocks := seating.AvoidOrphans(grid, seating.BestBlocks(grid, size, pref, 0, rule.AisleAfter), rule.AisleAfter, rule.OrphanSeat == model.OrphanRuleWarn)
+	if limit > 0 && len(blocks) > limit {
+		blocks = blocks[:limit]
+	}
+	return blocks
+}
+
+// FindBestSeats returns ranked blocks of adjacent free seats for a party, from the live seat map.
+func (h *Handler) FindBestSeats(c *gin.Context) {
+	var q BestSeatsRequest
+	if err := c.ShouldBindQuery(&q); err != nil || !q.normalize() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "party_size must be 1-" + strconv.Itoa(maxPartySize)})
+		return
+	}
+	s, err := h.Repo.GetScreening(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	blocks := h.bestBlocks(c.Request.Context(), s, q.PartySize, q.Prefer, q.Limit)
+	c.JSON(http.StatusOK, gin.H{"party_size": q.PartySize, "prefer": q.Prefer, "blocks": blocks})
+}
+
+// LockBestSeats finds the best block for the party and locks it atomically, like LockSeat.
+func (h *Handler) LockBestSeats(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var body BestSeatsRequest
+	if err := c.ShouldBindJSON(&body); err != nil || !body.normalize() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "party_size must be 1-" + strconv.Itoa(maxPartySize)})
+		return
+	}
+	s, err := h.Repo.GetScreening(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	blocks := h.bestBlocks(c.Request.Context(), s, body.PartySize, body.Prefer, 1)
+	if len(blocks) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "no adjacent seats available for this party size"})
+		return
+	}
+	h.lockSeats(c, userID, s, blocks[0].Seats)
+}
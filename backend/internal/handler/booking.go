@@ -1,13 +1,30 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
+	"cinema-booking/internal/lock"
 	"cinema-booking/internal/model"
+	"cinema-booking/internal/repository"
+	"cinema-booking/internal/seating"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// LockSeatRequest accepts a list of seats, seat labels such as "B12", or a single row/col (legacy clients).
+type LockSeatRequest struct {
+	Seats  []lock.Seat `json:"seats"`
+	Labels []string    `json:"labels"`
+	Row    *int        `json:"row"`
+	Col    *int        `json:"col"`
+}
+
+// LockSeat locks all requested seats atomically and creates one PENDING booking per seat.
 func (h *Handler) LockSeat(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
@@ -15,10 +32,7 @@ func (h *Handler) LockSeat(c *gin.Context) {
 		return
 	}
 	screeningID := c.Param("id")
-	var body struct {
-		Row int `json:"row" binding:"min=0"`
-		Col int `json:"col" binding:"min=0"`
-	}
+	var body LockSeatRequest
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -28,38 +42,220 @@ func (h *Handler) LockSeat(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
 		return
 	}
-	if body.Row < 0 || body.Row >= s.Rows || body.Col < 0 || body.Col >= s.Cols {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seat"})
+	seats, herr := seatsByLabel(s, body.Labels)
+	if herr != nil {
+		c.JSON(herr.Status, herr.Body)
 		return
 	}
-	lockID, err := h.Lock.Acquire(c.Request.Context(), screeningID, body.Row, body.Col)
-	if err != nil {
-		h.audit(model.EventLockFailed, map[string]any{"screening_id": screeningID, "row": body.Row, "col": body.Col, "error": err.Error()})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "lock failed"})
+	seats = append(seats, body.Seats...)
+	if len(seats) == 0 && body.Row != nil && body.Col != nil {
+		seats = []lock.Seat{{Row: *body.Row, Col: *body.Col}}
+	}
+	if len(seats) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no seats requested"})
 		return
 	}
+	h.lockSeats(c, userID, s, seats)
+}
+
+// seatsByLabel resolves seat labels against the screening's labeling.
+func seatsByLabel(s *model.Screening, labels []string) ([]lock.Seat, *holdError) {
+	seats := make([]lock.Seat, 0, len(labels))
+	for _, label := range labels {
+		row, col, ok := s.SeatByLabel(label)
+		if !ok {
+			return nil, &holdError{http.StatusBadRequest, gin.H{"error": "unknown seat label", "code": "INVALID_SEAT_LABEL", "label": label}}
+		}
+		seats = append(seats, lock.Seat{Row: row, Col: col})
+	}
+	return seats, nil
+}
+
+// holdOptions tweaks holdSeats for callers other than the plain lock endpoint.
+type holdOptions struct {
+	TTL     time.Duration // lock TTL; 0 means the default lock TTL
+	GroupID string        // set on every booking of a group hold
+}
+
+// seatHold is the result of a successful holdSeats.
+type seatHold struct {
+	LockID    string
+	Bookings  []*model.Booking
+	Order     *model.Order // nil for group holds
+	ExpiresAt time.Time
+	Warnings  []gin.H
+}
+
+// holdError carries the HTTP status and body for a failed holdSeats.
+type holdError struct {
+	Status int
+	Body   gin.H
+}
+
+// holdSeats validates seats, checks the seating rule and quota, acquires all locks and creates the PENDING bookings,
+// then broadcasts the locked seats.
+func (h *Handler) holdSeats(ctx context.Context, userID string, s *model.Screening, seats []lock.Seat, opts holdOptions) (*seatHold, *holdError) {
+	screeningID := s.ID.Hex()
+	if herr := h.checkOnSale(s); herr != nil {
+		return nil, herr
+	}
+	seen := make(map[lock.Seat]bool, len(seats))
+	for _, st := range seats {
+		if !s.IsSeat(st.Row, st.Col) {
+			return nil, &holdError{http.StatusBadRequest, gin.H{"error": "invalid seat", "seat": st}}
+		}
+		if seen[st] {
+			return nil, &holdError{http.StatusBadRequest, gin.H{"error": "duplicate seat"}}
+		}
+		seen[st] = true
+	}
+	var warnings []gin.H
+	if rule := s.SeatingRule; rule != nil && (rule.OrphanSeat == model.OrphanRuleWarn || rule.OrphanSeat == model.OrphanRuleReject) {
+		if orphans := seating.OrphanSeats(h.seatGrid(ctx, s), seats, rule.AisleAfter); len(orphans) > 0 {
+			if rule.OrphanSeat == model.OrphanRuleReject {
+				return nil, &holdError{http.StatusUnprocessableEntity, gin.H{
+					"error":        "selection would leave a single empty seat in the row",
+					"code":         "SEATING_RULE_VIOLATION",
+					"reason":       seating.ReasonOrphanSeat,
+					"orphan_seats": orphans,
+				}}
+			}
+			warnings = append(warnings, gin.H{"reason": seating.ReasonOrphanSeat, "orphan_seats": orphans})
+		}
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = h.lockTTL()
+	}
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	// Confirmed seats no longer hold a Redis lock, so a sold seat must be turned away from Mongo
+	if sold, err := h.Repo.CountSoldSeats(ctx, screeningID, seats); err != nil {
+		return nil, &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	} else if sold > 0 {
+		return nil, &holdError{http.StatusConflict, gin.H{"error": "seat already locked or booked"}}
+	}
+	booked, err := h.Repo.CountBookings(ctx, bson.M{"screening_id": screeningID, "user_id": userID, "status": "CONFIRMED"})
+	if err != nil {
+		return nil, &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	ok, held, err := h.Quota.Reserve(ctx, screeningID, userID, seats, booked, expiresAt)
+	if err != nil {
+		return nil, &holdError{http.StatusInternalServerError, gin.H{"error": "quota check failed"}}
+	}
+	if !ok {
+		return nil, &holdError{http.StatusForbidden, gin.H{
+			"error":  "seat quota exceeded for this screening",
+			"code":   "SEAT_QUOTA_EXCEEDED",
+			"limit":  h.Quota.Max(),
+			"held":   held,
+			"booked": booked,
+		}}
+	}
+	lockID, err := h.Lock.AcquireManyFor(ctx, screeningID, seats, ttl)
+	if err != nil {
+		_ = h.Quota.Release(ctx, screeningID, userID, seats)
+		labels := make([]string, len(seats))
+		for i, st := range seats {
+			labels[i] = s.SeatLabel(st.Row, st.Col)
+		}
+		h.audit(model.EventLockFailed, map[string]any{"screening_id": screeningID, "seats": seats, "seat_labels": labels, "error": err.Error()})
+		return nil, &holdError{http.StatusInternalServerError, gin.H{"error": "lock failed"}}
+	}
 	if lockID == "" {
-		c.JSON(http.StatusConflict, gin.H{"error": "seat already locked or booked"})
-		return
+		_ = h.Quota.Release(ctx, screeningID, userID, seats)
+		return nil, &holdError{http.StatusConflict, gin.H{"error": "seat already locked or booked"}}
 	}
-	b := &model.Booking{
-		ScreeningID: screeningID,
-		UserID:      userID,
-		SeatRow:     body.Row,
-		SeatCol:     body.Col,
-		Status:      "PENDING",
-		LockID:      lockID,
-		CreatedAt:   time.Now(),
+	// Checked again under the lock: a confirm releases its lock only after writing CONFIRMED, so a seat sold
+	// between the first check and AcquireManyFor shows up here
+	if sold, err := h.Repo.CountSoldSeats(ctx, screeningID, seats); err != nil || sold > 0 {
+		_ = h.Lock.ReleaseMany(ctx, screeningID, seats, lockID)
+		_ = h.Quota.Release(ctx, screeningID, userID, seats)
+		if err != nil {
+			return nil, &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+		}
+		return nil, &holdError{http.StatusConflict, gin.H{"error": "seat already locked or booked"}}
 	}
-	if err := h.Repo.CreateBooking(c.Request.Context(), b); err != nil {
-		_ = h.Lock.Release(c.Request.Context(), screeningID, body.Row, body.Col, lockID)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	bookings := make([]*model.Booking, len(seats))
+	for i, st := range seats {
+		seat := s.SeatAt(st.Row, st.Col)
+		bookings[i] = &model.Booking{
+			ScreeningID:   screeningID,
+			UserID:        userID,
+			SeatRow:       st.Row,
+			SeatCol:       st.Col,
+			SeatLabel:     seat.Label,
+			Status:        "PENDING",
+			LockID:        lockID,
+			LockExpiresAt: &expiresAt,
+			GroupID:       opts.GroupID,
+			SeatCategory:  seat.Category,
+			Price:         seat.Price,
+			CreatedAt:     now,
+		}
+	}
+	// A plain hold is checked out as one order; group seats are paid by each member separately
+	var order *model.Order
+	if opts.GroupID == "" {
+		order = &model.Order{ID: primitive.NewObjectID(), UserID: userID, ScreeningID: screeningID, Status: model.OrderPending, ExpiresAt: expiresAt, CreatedAt: now}
+		for _, b := range bookings {
+			b.OrderID = order.ID.Hex()
+		}
+	}
+	if err := h.Repo.CreateBookings(ctx, bookings); err != nil {
+		_ = h.Lock.ReleaseMany(ctx, screeningID, seats, lockID)
+		_ = h.Quota.Release(ctx, screeningID, userID, seats)
+		return nil, &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	if order != nil {
+		for _, b := range bookings {
+			order.Items = append(order.Items, model.OrderItem{Kind: model.OrderItemSeat, BookingID: b.ID.Hex(), SeatRow: b.SeatRow, SeatCol: b.SeatCol, SeatLabel: b.SeatLabel, Price: b.Price})
+			order.Total += b.Price
+		}
+		if err := h.Repo.CreateOrder(ctx, order); err != nil {
+			// Bookings stay valid on their own; they can still be confirmed one by one
+			log.Printf("create order: %v", err)
+			order = nil
+		}
+	}
+	// Broadcast all seat updates in one message so other users see LOCKED in real-time
+	states := make([]model.Seat, len(seats))
+	for i, b := range bookings {
+		states[i] = h.seatState(ctx, s, bookings, b.SeatRow, b.SeatCol)
 	}
-	// Broadcast seat update so other users see LOCKED in real-time
-	h.Hub.BroadcastSeatUpdate("screening:"+screeningID, h.seatState(c.Request.Context(), screeningID, []*model.Booking{b}, body.Row, body.Col))
+	h.Seats.Publish(ctx, screeningID, states...)
 	h.Hub.BroadcastAdmin("REFRESH", nil)
-	c.JSON(http.StatusOK, gin.H{"lock_id": lockID, "expires_in_seconds": 300, "booking_id": b.ID.Hex()})
+	return &seatHold{LockID: lockID, Bookings: bookings, Order: order, ExpiresAt: expiresAt, Warnings: warnings}, nil
+}
+
+// lockSeats holds seats with the default TTL and writes the lock response.
+func (h *Handler) lockSeats(c *gin.Context, userID string, s *model.Screening, seats []lock.Seat) {
+	hold, herr := h.holdSeats(c.Request.Context(), userID, s, seats, holdOptions{})
+	if herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	bookingIDs := make([]string, len(hold.Bookings))
+	labels := make([]string, len(hold.Bookings))
+	for i, b := range hold.Bookings {
+		bookingIDs[i] = b.ID.Hex()
+		labels[i] = b.SeatLabel
+	}
+	resp := gin.H{
+		"lock_id":            hold.LockID,
+		"expires_in_seconds": int(h.lockTTL().Seconds()),
+		"booking_id":         bookingIDs[0],
+		"booking_ids":        bookingIDs,
+		"seat_labels":        labels,
+	}
+	if hold.Order != nil {
+		resp["order_id"] = hold.Order.ID.Hex()
+		resp["total"] = hold.Order.Total
+	}
+	if len(hold.Warnings) > 0 {
+		resp["warnings"] = hold.Warnings
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) ConfirmPayment(c *gin.Context) {
@@ -88,23 +284,356 @@ func (h *Handler) ConfirmPayment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "booking already " + b.Status})
 		return
 	}
+	if b.GroupID != "" && b.ClaimedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "claim this group seat before paying"})
+		return
+	}
+	s, err := h.Repo.GetScreening(c.Request.Context(), b.ScreeningID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	if herr := h.checkOnSale(s); herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
 	// Verify lock still held
 	lockID, _ := h.Lock.GetLockID(c.Request.Context(), b.ScreeningID, b.SeatRow, b.SeatCol)
 	if lockID != b.LockID {
 		c.JSON(http.StatusConflict, gin.H{"error": "lock expired"})
 		return
 	}
-	if err := h.Repo.ConfirmBooking(c.Request.Context(), body.BookingID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if herr := h.confirmBooking(c.Request.Context(), s, b); herr != nil {
+		c.JSON(herr.Status, herr.Body)
 		return
 	}
-	// Keep key but we consider seat BOOKED; optionally delete lock or let it expire
-	_ = h.Lock.Release(c.Request.Context(), b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
-	h.audit(model.EventBookingSuccess, map[string]any{"booking_id": body.BookingID, "user_id": userID, "screening_id": b.ScreeningID})
-	_ = h.Pub.PublishBookingSuccess(c.Request.Context(), b.ScreeningID, userID, body.BookingID, b.SeatRow, b.SeatCol)
+	if b.OrderID != "" {
+		_, _ = h.Repo.SyncOrderStatus(c.Request.Context(), b.OrderID)
+	}
 	// Broadcast so seat shows BOOKED
 	bookings, _ := h.Repo.ListBookings(c.Request.Context(), map[string]interface{}{"screening_id": b.ScreeningID})
-	h.Hub.BroadcastSeatUpdate("screening:"+b.ScreeningID, h.seatState(c.Request.Context(), b.ScreeningID, bookings, b.SeatRow, b.SeatCol))
+	h.Seats.Publish(c.Request.Context(), b.ScreeningID, h.seatState(c.Request.Context(), s, bookings, b.SeatRow, b.SeatCol))
 	h.Hub.BroadcastAdmin("REFRESH", nil)
 	c.JSON(http.StatusOK, gin.H{"status": "confirmed"})
 }
+
+// confirmBooking marks a PENDING booking CONFIRMED, releases its lock and quota hold, audits and publishes BOOKING_SUCCESS.
+// The caller has verified ownership and that the lock is still held. On success b.PaidAmount holds the amount charged.
+func (h *Handler) confirmBooking(ctx context.Context, s *model.Screening, b *model.Booking) *holdError {
+	// Charge the price quoted at lock time; holds from before seat categories carry none
+	paid := b.Price
+	if b.SeatCategory == "" && paid == 0 {
+		_, paid = s.CategoryAt(b.SeatRow, b.SeatCol)
+	}
+	if err := h.Repo.ConfirmBooking(ctx, b.ID.Hex(), paid); err != nil {
+		if errors.Is(err, repository.ErrNotPending) || errors.Is(err, repository.ErrSeatTaken) {
+			return &holdError{http.StatusConflict, gin.H{"error": err.Error()}}
+		}
+		return &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	b.Status, b.PaidAmount = "CONFIRMED", paid
+	// Keep key but we consider seat BOOKED; optionally delete lock or let it expire
+	_ = h.Lock.Release(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
+	// The seat is now counted from Mongo as booked, no longer as a hold
+	_ = h.Quota.Release(ctx, b.ScreeningID, b.UserID, []lock.Seat{{Row: b.SeatRow, Col: b.SeatCol}})
+	h.audit(model.EventBookingSuccess, map[string]any{"booking_id": b.ID.Hex(), "user_id": b.UserID, "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol, "seat_label": b.SeatLabel})
+	_ = h.Pub.PublishBookingSuccess(ctx, b.ScreeningID, b.UserID, b.ID.Hex(), b.SeatRow, b.SeatCol, b.SeatLabel)
+	return nil
+}
+
+// ExtendLock pushes back the lock expiry of a PENDING booking (and the seats locked with it), up to LockMaxExtensions times.
+func (h *Handler) ExtendLock(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ctx := c.Request.Context()
+	b, err := h.Repo.GetBookingByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if b.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your booking"})
+		return
+	}
+	if b.Status != "PENDING" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "booking already " + b.Status})
+		return
+	}
+	// Group holds already run on the longer group TTL; extending would cut them down to the default lock TTL
+	if b.GroupID != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "group holds cannot be extended"})
+		return
+	}
+	if b.ExtendCount >= h.LockMaxExtensions {
+		c.JSON(http.StatusConflict, gin.H{"error": "lock extension limit reached"})
+		return
+	}
+	held, err := h.Repo.ListBookings(ctx, bson.M{"screening_id": b.ScreeningID, "lock_id": b.LockID, "status": "PENDING"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// The count is shared by the whole hold, so every request for it is checked against the same booking
+	gate := b
+	for _, hb := range held {
+		if hb.ID.Hex() < gate.ID.Hex() {
+			gate = hb
+		}
+	}
+	unlocksAt := time.Now().Add(h.lockTTL())
+	extended, err := h.Repo.ExtendBookingLock(ctx, b.ScreeningID, b.LockID, gate.ID.Hex(), h.LockMaxExtensions, unlocksAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !extended {
+		c.JSON(http.StatusConflict, gin.H{"error": "lock extension limit reached"})
+		return
+	}
+	// Redis follows only once Mongo has granted the extension, so a request refused at the limit changes nothing
+	for _, hb := range held {
+		ok, err := h.Lock.Extend(ctx, hb.ScreeningID, hb.SeatRow, hb.SeatCol, hb.LockID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusConflict, gin.H{"error": "lock expired"})
+			return
+		}
+	}
+	bookingIDs := make([]string, len(held))
+	heldSeats := make([]lock.Seat, len(held))
+	labels := make([]string, len(held))
+	for i, hb := range held {
+		bookingIDs[i] = hb.ID.Hex()
+		heldSeats[i] = lock.Seat{Row: hb.SeatRow, Col: hb.SeatCol}
+		labels[i] = hb.SeatLabel
+	}
+	_ = h.Quota.Extend(ctx, b.ScreeningID, userID, heldSeats, unlocksAt)
+	if b.OrderID != "" {
+		_ = h.Repo.SetOrderExpiry(ctx, b.OrderID, unlocksAt)
+	}
+	payload := map[string]any{
+		"screening_id": b.ScreeningID,
+		"user_id":      userID,
+		"booking_ids":  bookingIDs,
+		"seat_labels":  labels,
+		"unlocks_at":   unlocksAt,
+	}
+	h.audit(model.EventLockExtended, payload)
+	h.Hub.BroadcastNotification("screening:"+b.ScreeningID, model.EventLockExtended, payload)
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, gin.H{
+		"booking_ids":          bookingIDs,
+		"unlocks_at":           unlocksAt,
+		"expires_in_seconds":   int(h.lockTTL().Seconds()),
+		"extensions_remaining": h.LockMaxExtensions - b.ExtendCount - 1,
+	})
+}
+
+// CancelBooking lets the owner release a PENDING seat hold immediately, or cancel a CONFIRMED booking under the refund policy.
+func (h *Handler) CancelBooking(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	b, err := h.Repo.GetBookingByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if b.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your booking"})
+		return
+	}
+	switch b.Status {
+	case "PENDING":
+		h.cancelPending(c, b)
+	case "CONFIRMED":
+		h.refundConfirmed(c, b)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "booking already " + b.Status})
+	}
+}
+
+func (h *Handler) cancelPending(c *gin.Context, b *model.Booking) {
+	ctx := c.Request.Context()
+	// Only set CANCELLED if still PENDING (confirm or expiry may have won the race)
+	updated, err := h.Repo.SetBookingStatusIfPending(ctx, b.ID.Hex(), "CANCELLED")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusConflict, gin.H{"error": "booking is no longer pending"})
+		return
+	}
+	_ = h.Lock.Release(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
+	_ = h.Quota.Release(ctx, b.ScreeningID, b.UserID, []lock.Seat{{Row: b.SeatRow, Col: b.SeatCol}})
+	if b.OrderID != "" {
+		_, _ = h.Repo.SyncOrderStatus(ctx, b.OrderID)
+	}
+	if b.GroupID != "" && b.ClaimedAt != nil {
+		// The member gave their seat up and may claim another one of the group
+		_ = h.Repo.ReleaseGroupClaim(ctx, b.GroupID, b.UserID)
+	}
+	h.audit(model.EventBookingCancelled, map[string]any{"booking_id": b.ID.Hex(), "user_id": b.UserID, "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol, "seat_label": b.SeatLabel})
+	_ = h.Pub.PublishSeatReleased(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel)
+	h.broadcastSeat(ctx, b.ScreeningID, b.SeatRow, b.SeatCol)
+	c.JSON(http.StatusOK, gin.H{"status": "cancelled"})
+}
+
+func (h *Handler) refundConfirmed(c *gin.Context, b *model.Booking) {
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, b.ScreeningID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	percent, ok := h.Refund.Percent(s.ScreenAt, time.Now())
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "screening already started"})
+		return
+	}
+	amount := refundAmount(b, percent)
+	updated, err := h.Repo.CancelConfirmedBooking(ctx, b.ID.Hex(), percent, amount)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusConflict, gin.H{"error": "booking is no longer confirmed"})
+		return
+	}
+	if b.OrderID != "" {
+		_, _ = h.Repo.SyncOrderStatus(ctx, b.OrderID)
+	}
+	h.notifyTransferRevoked(b, "cancelled")
+	h.audit(model.EventBookingRefunded, map[string]any{"booking_id": b.ID.Hex(), "user_id": b.UserID, "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol, "seat_label": b.SeatLabel, "refund_percent": percent, "refund_amount": amount})
+	_ = h.Pub.PublishBookingRefunded(ctx, b.ScreeningID, b.UserID, b.ID.Hex(), b.SeatRow, b.SeatCol, b.SeatLabel, percent)
+	// Seat goes back on sale
+	h.broadcastSeat(ctx, b.ScreeningID, b.SeatRow, b.SeatCol)
+	c.JSON(http.StatusOK, gin.H{"status": "cancelled", "refund_percent": percent, "refund_amount": amount})
+}
+
+// ExchangeSeat moves a CONFIRMED booking to another free seat in the same screening.
+// The target seat is locked for the duration of the move so the customer never holds zero or two seats.
+func (h *Handler) ExchangeSeat(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var body struct {
+		lock.Seat
+		Label string `json:"label"` // instead of row/col
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	b, err := h.Repo.GetBookingByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if b.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your booking"})
+		return
+	}
+	if b.Status != "CONFIRMED" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only confirmed bookings can change seat"})
+		return
+	}
+	s, err := h.Repo.GetScreening(ctx, b.ScreeningID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	if body.Label != "" {
+		seats, herr := seatsByLabel(s, []string{body.Label})
+		if herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		body.Seat = seats[0]
+	}
+	if !s.IsSeat(body.Row, body.Col) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid seat"})
+		return
+	}
+	if herr := h.checkOnSale(s); herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	if herr := h.checkAdmission(c, s.ID.Hex()); herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	if body.Row == b.SeatRow && body.Col == b.SeatCol {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "already in this seat"})
+		return
+	}
+	target := s.SeatAt(body.Row, body.Col)
+	// The amount paid stays with the booking, so only same-category moves are allowed
+	if from, _ := s.CategoryAt(b.SeatRow, b.SeatCol); from != target.Category {
+		c.JSON(http.StatusConflict, gin.H{"error": "target seat is in another price category", "code": "CATEGORY_MISMATCH"})
+		return
+	}
+	lockID, err := h.Lock.Acquire(ctx, b.ScreeningID, body.Row, body.Col)
+	if err != nil {
+		h.audit(model.EventLockFailed, map[string]any{"screening_id": b.ScreeningID, "row": body.Row, "col": body.Col, "seat_label": target.Label, "error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "lock failed"})
+		return
+	}
+	if lockID == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "seat already locked or booked"})
+		return
+	}
+	defer h.Lock.Release(ctx, b.ScreeningID, body.Row, body.Col, lockID)
+	// Confirmed seats no longer hold a Redis lock, so check Mongo too
+	bookings, _ := h.Repo.ListBookings(ctx, bson.M{"screening_id": b.ScreeningID})
+	if h.seatState(ctx, s, bookings, body.Row, body.Col).Status == model.SeatBooked {
+		c.JSON(http.StatusConflict, gin.H{"error": "seat already locked or booked"})
+		return
+	}
+	moved, err := h.Repo.MoveConfirmedBooking(ctx, b.ID.Hex(), b.SeatRow, b.SeatCol, body.Row, body.Col, target.Label)
+	if errors.Is(err, repository.ErrSeatTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !moved {
+		c.JSON(http.StatusConflict, gin.H{"error": "booking changed, try again"})
+		return
+	}
+	h.audit(model.EventSeatExchanged, map[string]any{
+		"booking_id":   b.ID.Hex(),
+		"user_id":      userID,
+		"screening_id": b.ScreeningID,
+		"from_row":     b.SeatRow,
+		"from_col":     b.SeatCol,
+		"from_label":   b.SeatLabel,
+		"to_row":       body.Row,
+		"to_col":       body.Col,
+		"to_label":     target.Label,
+	})
+	h.notifyTransferRevoked(b, "exchanged")
+	_ = h.Pub.PublishSeatReleased(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel)
+	bookings, _ = h.Repo.ListBookings(ctx, bson.M{"screening_id": b.ScreeningID})
+	h.Seats.Publish(ctx, b.ScreeningID,
+		h.seatState(ctx, s, bookings, b.SeatRow, b.SeatCol),
+		h.seatState(ctx, s, bookings, body.Row, body.Col),
+	)
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, gin.H{"status": "exchanged", "seat_row": body.Row, "seat_col": body.Col, "seat_label": target.Label})
+}
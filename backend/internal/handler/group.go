@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/repository"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateGroupHold holds a block of seats for the organizer with the longer group TTL and returns a share link.
+// Friends claim a seat each and pay for it with ConfirmPayment; unclaimed or unpaid seats expire like any hold.
+func (h *Handler) CreateGroupHold(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var body struct {
+		Seats  []lock.Seat `json:"seats"`
+		Labels []string    `json:"labels"` // instead of or in addition to seats
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	seats, herr := seatsByLabel(s, body.Labels)
+	if herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	body.Seats = append(seats, body.Seats...)
+	if len(body.Seats) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a group hold needs at least 2 seats"})
+		return
+	}
+	gid := primitive.NewObjectID()
+	hold, herr := h.holdSeats(ctx, userID, s, body.Seats, holdOptions{TTL: h.GroupHoldTTL, GroupID: gid.Hex()})
+	if herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	g := &model.GroupHold{
+		ID:          gid,
+		ScreeningID: s.ID.Hex(),
+		OrganizerID: userID,
+		LockID:      hold.LockID,
+		ExpiresAt:   hold.ExpiresAt,
+	}
+	if err := h.Repo.CreateGroupHold(ctx, g); err != nil {
+		_ = h.Lock.ReleaseMany(ctx, g.ScreeningID, body.Seats, hold.LockID)
+		_ = h.Quota.Release(ctx, g.ScreeningID, userID, body.Seats)
+		states := make([]model.Seat, 0, len(hold.Bookings))
+		for _, b := range hold.Bookings {
+			_, _ = h.Repo.SetBookingStatusIfPending(ctx, b.ID.Hex(), "CANCELLED")
+			_ = h.Pub.PublishSeatReleased(ctx, g.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel)
+			states = append(states, s.SeatAt(b.SeatRow, b.SeatCol))
+		}
+		h.Seats.Publish(ctx, g.ScreeningID, states...)
+		h.Hub.BroadcastAdmin("REFRESH", nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	bookingIDs := make([]string, len(hold.Bookings))
+	labels := make([]string, len(hold.Bookings))
+	for i, b := range hold.Bookings {
+		bookingIDs[i] = b.ID.Hex()
+		labels[i] = b.SeatLabel
+	}
+	h.audit(model.EventGroupHoldCreated, map[string]any{"group_id": gid.Hex(), "screening_id": g.ScreeningID, "user_id": userID, "booking_ids": bookingIDs, "seat_labels": labels})
+	c.JSON(http.StatusCreated, gin.H{
+		"group_id":    gid.Hex(),
+		"share_path":  "/groups/" + gid.Hex(),
+		"expires_at":  g.ExpiresAt,
+		"booking_ids": bookingIDs,
+		"seat_labels": labels,
+	})
+}
+
+// GroupSeat is one seat of a group hold as shown to members.
+type GroupSeat struct {
+	BookingID string `json:"booking_id"`
+	Row       int    `json:"row"`
+	Col       int    `json:"col"`
+	Label     string `json:"label,omitempty"`
+	Status    string `json:"status"`
+	Claimed   bool   `json:"claimed"`
+	UserID    string `json:"user_id,omitempty"`
+}
+
+// GetGroupHold returns a group hold and the claim/payment state of each of its seats.
+func (h *Handler) GetGroupHold(c *gin.Context) {
+	ctx := c.Request.Context()
+	g, err := h.Repo.GetGroupHold(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+	bookings, err := h.Repo.ListBookings(ctx, bson.M{"group_id": g.ID.Hex()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	seats := make([]GroupSeat, len(bookings))
+	for i, b := range bookings {
+		seats[i] = GroupSeat{BookingID: b.ID.Hex(), Row: b.SeatRow, Col: b.SeatCol, Label: b.SeatLabel, Status: b.Status, Claimed: b.ClaimedAt != nil}
+		if b.ClaimedAt != nil {
+			seats[i].UserID = b.UserID
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"group": g, "seats": seats, "expired": time.Now().After(g.ExpiresAt)})
+}
+
+// ClaimGroupSeat gives the caller one unclaimed seat of the group (a specific booking_id, or the first free one).
+func (h *Handler) ClaimGroupSeat(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var body struct {
+		BookingID string `json:"booking_id"`
+	}
+	_ = c.ShouldBindJSON(&body)
+	ctx := c.Request.Context()
+	g, err := h.Repo.GetGroupHold(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return
+	}
+	if time.Now().After(g.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "group hold expired"})
+		return
+	}
+	s, err := h.Repo.GetScreening(ctx, g.ScreeningID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	if herr := h.checkOnSale(s); herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	if herr := h.checkAdmission(c, s.ID.Hex()); herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	bookings, err := h.Repo.ListBookings(ctx, bson.M{"group_id": g.ID.Hex()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var target *model.Booking
+	for _, b := range bookings {
+		if b.ClaimedAt != nil && b.UserID == userID && b.Status != "TIMEOUT" && b.Status != "CANCELLED" {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already claimed a seat in this group"})
+			return
+		}
+		if target == nil && b.ClaimedAt == nil && b.Status == "PENDING" && (body.BookingID == "" || b.ID.Hex() == body.BookingID) {
+			target = b
+		}
+	}
+	if target == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "no unclaimed seat available"})
+		return
+	}
+	seat := []lock.Seat{{Row: target.SeatRow, Col: target.SeatCol}}
+	if userID != g.OrganizerID {
+		booked, err := h.Repo.CountBookings(ctx, bson.M{"screening_id": g.ScreeningID, "user_id": userID, "status": "CONFIRMED"})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ok, held, err := h.Quota.Reserve(ctx, g.ScreeningID, userID, seat, booked, g.ExpiresAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "quota check failed"})
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "seat quota exceeded for this screening", "code": "SEAT_QUOTA_EXCEEDED", "limit": h.Quota.Max(), "held": held, "booked": booked})
+			return
+		}
+	}
+	claimed, err := h.Repo.ClaimGroupSeat(ctx, target.ID.Hex(), g.ID.Hex(), userID)
+	if err != nil || !claimed {
+		if userID != g.OrganizerID {
+			_ = h.Quota.Release(ctx, g.ScreeningID, userID, seat)
+		}
+		if errors.Is(err, repository.ErrAlreadyClaimed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already claimed a seat in this group"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "seat was just claimed, try again"})
+		return
+	}
+	if userID != g.OrganizerID {
+		// The seat now counts against the member, not the organizer
+		_ = h.Quota.Release(ctx, g.ScreeningID, g.OrganizerID, seat)
+	}
+	payload := map[string]any{"group_id": g.ID.Hex(), "booking_id": target.ID.Hex(), "screening_id": g.ScreeningID, "user_id": userID, "seat_row": target.SeatRow, "seat_col": target.SeatCol, "seat_label": target.SeatLabel}
+	h.audit(model.EventGroupSeatClaimed, payload)
+	h.Hub.NotifyUser(g.OrganizerID, model.EventGroupSeatClaimed, payload)
+	h.broadcastSeat(ctx, g.ScreeningID, target.SeatRow, target.SeatCol)
+	c.JSON(http.StatusOK, gin.H{"booking_id": target.ID.Hex(), "seat_row": target.SeatRow, "seat_col": target.SeatCol, "seat_label": target.SeatLabel, "expires_at": g.ExpiresAt})
+}
@@ -2,22 +2,41 @@ package handler
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"cinema-booking/internal/lock"
 	"cinema-booking/internal/model"
 	"cinema-booking/internal/mq"
+	"cinema-booking/internal/quota"
 	"cinema-booking/internal/repository"
+	"cinema-booking/internal/seatmap"
+	"cinema-booking/internal/waitlist"
+	"cinema-booking/internal/waitroom"
+	"cinema-booking/internal/worker"
 	"cinema-booking/internal/ws"
+	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	Repo            *repository.MongoRepo
-	Lock            *lock.Manager
-	Hub             *ws.Hub
-	Pub             *mq.Publisher
-	JWTSecret       string
-	LockTTLSeconds  int
-	OnAudit         func(event string, payload map[string]any)
+	Repo              *repository.MongoRepo
+	Lock              *lock.Manager
+	Hub               *ws.Hub
+	Seats             *seatmap.Versions
+	Pub               *mq.Publisher
+	JWTSecret         string
+	LockTTLSeconds    int
+	LockMaxExtensions int
+	Quota             *quota.Limiter
+	Waitlist          *waitlist.Service
+	Room              *waitroom.Room
+	Refund            RefundPolicy
+	GroupHoldTTL      time.Duration
+	SalesCutoff       time.Duration
+	CleaningBuffer    time.Duration
+	TransferOfferTTL  time.Duration
+	Reconciler        *worker.Reconciler
+	OnAudit           func(event string, payload map[string]any)
 }
 
 func (h *Handler) audit(event string, payload map[string]any) {
@@ -26,8 +45,46 @@ func (h *Handler) audit(event string, payload map[string]any) {
 	}
 }
 
-func (h *Handler) seatState(ctx context.Context, screeningID string, bookings []*model.Booking, row, col int) model.Seat {
-	st := model.Seat{Row: row, Col: col, Status: model.SeatAvailable}
+// lockTTL is the seat lock TTL the handlers report to clients (defaults to 5 minutes).
+func (h *Handler) lockTTL() time.Duration {
+	if h.LockTTLSeconds > 0 {
+		return time.Duration(h.LockTTLSeconds) * time.Second
+	}
+	return 300 * time.Second
+}
+
+// withSaleState fills the computed SaleState of s for the current time and returns s.
+func (h *Handler) withSaleState(s *model.Screening) *model.Screening {
+	s.SaleState = s.SaleStateAt(time.Now(), h.SalesCutoff)
+	return s
+}
+
+// checkOnSale returns a holdError unless seats of s can be sold right now.
+func (h *Handler) checkOnSale(s *model.Screening) *holdError {
+	if st := h.withSaleState(s).SaleState; st != model.SaleOnSale {
+		return &holdError{http.StatusConflict, gin.H{"error": "screening is not on sale", "code": "SALES_CLOSED", "sale_state": st}}
+	}
+	return nil
+}
+
+// broadcastSeat re-reads the screening's bookings and pushes the current state of one seat to its room and the admin room.
+func (h *Handler) broadcastSeat(ctx context.Context, screeningID string, row, col int) {
+	s, err := h.Repo.GetScreening(ctx, screeningID)
+	if err != nil {
+		return
+	}
+	bookings, _ := h.Repo.ListBookings(ctx, map[string]interface{}{"screening_id": screeningID})
+	h.Seats.Publish(ctx, screeningID, h.seatState(ctx, s, bookings, row, col))
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+}
+
+// seatGrid builds the full Rows x Cols seat map of a screening, including live Redis locks.
+func (h *Handler) seatGrid(ctx context.Context, s *model.Screening) [][]model.Seat {
+	return seatmap.Grid(ctx, h.Repo, h.Lock, s)
+}
+
+func (h *Handler) seatState(ctx context.Context, s *model.Screening, bookings []*model.Booking, row, col int) model.Seat {
+	st := s.SeatAt(row, col)
 	for _, b := range bookings {
 		if b.SeatRow == row && b.SeatCol == col {
 			if b.Status == "CONFIRMED" {
@@ -36,7 +93,7 @@ func (h *Handler) seatState(ctx context.Context, screeningID string, bookings []
 				return st
 			}
 			if b.Status == "PENDING" && b.LockID != "" {
-				lockID, _ := h.Lock.GetLockID(ctx, screeningID, row, col)
+				lockID, _ := h.Lock.GetLockID(ctx, s.ID.Hex(), row, col)
 				if lockID == b.LockID {
 					st.Status = model.SeatLocked
 					st.LockID = b.LockID
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// layoutMapKinds maps the characters of a layout drawing to cell kinds.
+var layoutMapKinds = map[rune]string{
+	'S': model.CellSeat,
+	'W': model.CellWheelchair,
+	'C': model.CellCompanion,
+	'_': model.CellAisle,
+	'#': model.CellStairs,
+	'.': model.CellGap,
+}
+
+// CreateLayout stores a hall layout (admin). The hall is given either as rows, cols and the cells that are not
+// regular seats, or as a drawing in map, one string per row: S seat, W wheelchair, C companion, _ aisle,
+// # stairs, . gap. Labels can be set through cells in both forms.
+func (h *Handler) CreateLayout(c *gin.Context) {
+	var body struct {
+		Name  string             `json:"name" binding:"required"`
+		Rows  int                `json:"rows"`
+		Cols  int                `json:"cols"`
+		Map   []string           `json:"map"`
+		Cells []model.LayoutCell `json:"cells"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	l := &model.Layout{Name: body.Name, Rows: body.Rows, Cols: body.Cols}
+	cells := map[model.SeatRef]model.LayoutCell{}
+	var order []model.SeatRef
+	put := func(cell model.LayoutCell) {
+		ref := model.SeatRef{Row: cell.Row, Col: cell.Col}
+		if _, ok := cells[ref]; !ok {
+			order = append(order, ref)
+		}
+		cells[ref] = cell
+	}
+	if len(body.Map) > 0 {
+		l.Rows, l.Cols = len(body.Map), 0
+		for r, line := range body.Map {
+			col := 0
+			for _, ch := range line {
+				kind, ok := layoutMapKinds[ch]
+				if !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("map row %d: unknown cell %q", r, ch)})
+					return
+				}
+				if kind != model.CellSeat {
+					put(model.LayoutCell{Row: r, Col: col, Kind: kind})
+				}
+				col++
+			}
+			if col > l.Cols {
+				l.Cols = col
+			}
+		}
+		// Short rows are padded with gaps
+		for r, line := range body.Map {
+			for col := len([]rune(line)); col < l.Cols; col++ {
+				put(model.LayoutCell{Row: r, Col: col, Kind: model.CellGap})
+			}
+		}
+	}
+	if l.Rows < 1 || l.Cols < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "layout needs rows and cols, or a map"})
+		return
+	}
+	for _, cell := range body.Cells {
+		if cell.Row < 0 || cell.Row >= l.Rows || cell.Col < 0 || cell.Col >= l.Cols {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("cell %d:%d out of range", cell.Row, cell.Col)})
+			return
+		}
+		if cell.Kind == "" {
+			// A label-only cell keeps the kind drawn in the map
+			cell.Kind = cells[model.SeatRef{Row: cell.Row, Col: cell.Col}].Kind
+		}
+		switch cell.Kind {
+		case "", model.CellSeat, model.CellWheelchair, model.CellCompanion, model.CellAisle, model.CellStairs, model.CellGap:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown cell kind " + cell.Kind})
+			return
+		}
+		put(cell)
+	}
+	seats := 0
+	for r := 0; r < l.Rows; r++ {
+		for col := 0; col < l.Cols; col++ {
+			kind := cells[model.SeatRef{Row: r, Col: col}].Kind
+			if kind == "" || model.IsSeatKind(kind) {
+				seats++
+			}
+		}
+	}
+	if seats == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "layout has no seats"})
+		return
+	}
+	for _, ref := range order {
+		cell := cells[ref]
+		if (cell.Kind == "" || cell.Kind == model.CellSeat) && cell.Label == "" {
+			continue // a plain seat needs no entry
+		}
+		l.Cells = append(l.Cells, cell)
+	}
+	// Custom labels must not repeat each other or a generated label; halls check again with their own labeling
+	if dup := (&model.Screening{Rows: l.Rows, Cols: l.Cols, Layout: l}).DuplicateLabel(); dup != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "seat label " + dup + " is used by more than one seat"})
+		return
+	}
+	if err := h.Repo.CreateLayout(c.Request.Context(), l); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"layout": l, "seats": seats})
+}
+
+// ListLayouts returns all layouts without their cells (admin).
+func (h *Handler) ListLayouts(c *gin.Context) {
+	list, err := h.Repo.ListLayouts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) GetLayout(c *gin.Context) {
+	l, err := h.Repo.GetLayout(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "layout not found"})
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+// DeleteLayout removes a layout that no screening references (admin).
+func (h *Handler) DeleteLayout(c *gin.Context) {
+	err := h.Repo.DeleteLayout(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": "layout is used by screenings"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "layout not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
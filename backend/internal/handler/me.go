@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cinema-booking/internal/model"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	myBookingsPageSize    = 20
+	myBookingsMaxPageSize = 100
+)
+
+var bookingStatuses = map[string]bool{"PENDING": true, "CONFIRMED": true, "TIMEOUT": true, "CANCELLED": true}
+
+// MyBookingScreening is the screening and movie shown with each of the user's bookings.
+type MyBookingScreening struct {
+	ID        string    `json:"id"`
+	MovieID   string    `json:"movie_id"`
+	MovieName string    `json:"movie_name"`
+	ScreenAt  time.Time `json:"screen_at"`
+	SaleState string    `json:"sale_state"`
+}
+
+// Cancellation tells the client whether CancelBooking would succeed now and what it would refund.
+type Cancellation struct {
+	Allowed       bool    `json:"allowed"`
+	RefundPercent int     `json:"refund_percent"`
+	RefundAmount  float64 `json:"refund_amount"`
+	Reason        string  `json:"reason,omitempty"` // set when not allowed
+}
+
+// MyBooking is one item of GET /api/me/bookings.
+type MyBooking struct {
+	Booking         *model.Booking      `json:"booking"`
+	Screening       *MyBookingScreening `json:"screening,omitempty"`
+	LockExpiresAt   *time.Time          `json:"lock_expires_at,omitempty"` // PENDING only
+	LockSecondsLeft int                 `json:"lock_seconds_left,omitempty"`
+	Cancellation    Cancellation        `json:"cancellation"`
+}
+
+// ListMyBookings lists the caller's bookings.
+// Query: tab=upcoming|past (by screening time, default upcoming), status=PENDING,CONFIRMED,..., page, page_size.
+// Upcoming screenings come soonest first, past ones most recent first.
+func (h *Handler) ListMyBookings(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	tab := c.DefaultQuery("tab", "upcoming")
+	if tab != "upcoming" && tab != "past" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tab must be upcoming or past"})
+		return
+	}
+	var statuses []string
+	if raw := strings.TrimSpace(c.Query("status")); raw != "" {
+		for _, st := range strings.Split(raw, ",") {
+			st = strings.ToUpper(strings.TrimSpace(st))
+			if !bookingStatuses[st] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status " + st})
+				return
+			}
+			statuses = append(statuses, st)
+		}
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	size, _ := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(myBookingsPageSize)))
+	if size < 1 {
+		size = myBookingsPageSize
+	}
+	if size > myBookingsMaxPageSize {
+		size = myBookingsMaxPageSize
+	}
+
+	ctx := c.Request.Context()
+	filter := bson.M{"user_id": userID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+	// Only the screenings the user has bookings for are loaded, whatever the size of the schedule
+	screeningIDs, err := h.Repo.BookingScreeningIDs(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	screenings, err := h.Repo.FindScreeningsByID(ctx, screeningIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// FindScreeningsByID is sorted by screen_at ascending; past reads it backwards
+	now := time.Now()
+	byID := make(map[string]*model.Screening, len(screenings))
+	ids := []string{}
+	for _, s := range screenings {
+		byID[s.ID.Hex()] = s
+		if (tab == "upcoming") == s.ScreenAt.After(now) {
+			ids = append(ids, s.ID.Hex())
+		}
+	}
+	if tab == "past" {
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	}
+	filter["screening_id"] = bson.M{"$in": ids}
+	list, total, err := h.Repo.ListBookingsPage(ctx, filter, ids, (page-1)*size, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	items := make([]MyBooking, len(list))
+	for i, b := range list {
+		items[i] = h.myBooking(b, byID[b.ScreeningID], now)
+	}
+	c.JSON(http.StatusOK, gin.H{"tab": tab, "items": items, "page": page, "page_size": size, "total": total})
+}
+
+func (h *Handler) myBooking(b *model.Booking, s *model.Screening, now time.Time) MyBooking {
+	item := MyBooking{Booking: b}
+	if s != nil {
+		h.withSaleState(s)
+		item.Screening = &MyBookingScreening{
+			ID:        s.ID.Hex(),
+			MovieID:   s.MovieID,
+			MovieName: s.MovieName,
+			ScreenAt:  s.ScreenAt,
+			SaleState: s.SaleState,
+		}
+	}
+	if b.Status == "PENDING" {
+		expiresAt := b.CreatedAt.Add(h.lockTTL())
+		if b.LockExpiresAt != nil {
+			expiresAt = *b.LockExpiresAt
+		}
+		item.LockExpiresAt = &expiresAt
+		if left := int(expiresAt.Sub(now).Seconds()); left > 0 {
+			item.LockSecondsLeft = left
+		}
+	}
+	item.Cancellation = h.cancellation(b, s, now)
+	return item
+}
+
+// cancellation mirrors the checks of CancelBooking without changing anything.
+func (h *Handler) cancellation(b *model.Booking, s *model.Screening, now time.Time) Cancellation {
+	switch b.Status {
+	case "PENDING":
+		return Cancellation{Allowed: true}
+	case "CONFIRMED":
+		if s == nil {
+			return Cancellation{Reason: "screening not found"}
+		}
+		percent, ok := h.Refund.Percent(s.ScreenAt, now)
+		if !ok {
+			return Cancellation{Reason: "screening already started"}
+		}
+		return Cancellation{Allowed: true, RefundPercent: percent, RefundAmount: refundAmount(b, percent)}
+	default:
+		return Cancellation{Reason: "booking already " + b.Status}
+	}
+}
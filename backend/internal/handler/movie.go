@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/repository"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NowShowing is a movie with its upcoming screenings.
+type NowShowing struct {
+	Movie      *model.Movie       `json:"movie"`
+	Screenings []*model.Screening `json:"screenings"`
+}
+
+// ListNowShowing returns the movies with screenings still to come (optionally of ?cinema_id), ordered by their
+// next screening. Screenings whose sales have closed are left out.
+func (h *Handler) ListNowShowing(c *gin.Context) {
+	ctx := c.Request.Context()
+	filter := bson.M{"screen_at": bson.M{"$gt": time.Now()}}
+	if cinemaID := c.Query("cinema_id"); cinemaID != "" {
+		filter["cinema_id"] = cinemaID
+	}
+	screenings, err := h.Repo.FindScreenings(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var movieIDs []string
+	for _, s := range screenings {
+		movieIDs = append(movieIDs, s.MovieID)
+	}
+	movies, err := h.Repo.GetMovies(ctx, movieIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	out := []*NowShowing{}
+	byMovie := make(map[string]*NowShowing)
+	for _, s := range screenings {
+		if h.withSaleState(s).SaleState == model.SaleClosed {
+			continue
+		}
+		entry := byMovie[s.MovieID]
+		if entry == nil {
+			m := movies[s.MovieID]
+			if m == nil {
+				continue
+			}
+			entry = &NowShowing{Movie: m}
+			byMovie[s.MovieID] = entry
+			out = append(out, entry)
+		}
+		entry.Screenings = append(entry.Screenings, s)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetMovieShowtimes returns a movie with its upcoming screenings.
+func (h *Handler) GetMovieShowtimes(c *gin.Context) {
+	ctx := c.Request.Context()
+	m, err := h.Repo.GetMovie(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+	screenings, err := h.Repo.FindScreenings(ctx, bson.M{"movie_id": m.ID.Hex(), "screen_at": bson.M{"$gt": time.Now()}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, s := range screenings {
+		h.withSaleState(s)
+	}
+	if screenings == nil {
+		screenings = []*model.Screening{}
+	}
+	c.JSON(http.StatusOK, NowShowing{Movie: m, Screenings: screenings})
+}
+
+// ListMovies returns the whole catalog (admin).
+func (h *Handler) ListMovies(c *gin.Context) {
+	list, err := h.Repo.ListMovies(c.Request.Context(), bson.M{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) GetMovie(c *gin.Context) {
+	m, err := h.Repo.GetMovie(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+type movieBody struct {
+	Title          string     `json:"title" binding:"required"`
+	RuntimeMinutes int        `json:"runtime_minutes" binding:"required,min=1"`
+	AgeRating      string     `json:"age_rating"`
+	Genres         []string   `json:"genres"`
+	Languages      []string   `json:"languages"`
+	PosterURL      string     `json:"poster_url"`
+	ReleaseDate    *time.Time `json:"release_date"`
+	EndDate        *time.Time `json:"end_date"`
+}
+
+// bindMovie parses and checks a movie body; it writes the error response.
+func bindMovie(c *gin.Context) (*model.Movie, bool) {
+	var body movieBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	title := strings.TrimSpace(body.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
+		return nil, false
+	}
+	if body.ReleaseDate != nil && body.EndDate != nil && body.EndDate.Before(*body.ReleaseDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date is before release_date"})
+		return nil, false
+	}
+	return &model.Movie{
+		Title:          title,
+		RuntimeMinutes: body.RuntimeMinutes,
+		AgeRating:      strings.TrimSpace(body.AgeRating),
+		Genres:         body.Genres,
+		Languages:      body.Languages,
+		PosterURL:      strings.TrimSpace(body.PosterURL),
+		ReleaseDate:    body.ReleaseDate,
+		EndDate:        body.EndDate,
+	}, true
+}
+
+func (h *Handler) CreateMovie(c *gin.Context) {
+	m, ok := bindMovie(c)
+	if !ok {
+		return
+	}
+	if err := h.Repo.CreateMovie(c.Request.Context(), m); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, m)
+}
+
+// UpdateMovie replaces a movie's metadata; a new title is copied to its screenings. Screenings already scheduled
+// keep their runtime and occupied slot.
+func (h *Handler) UpdateMovie(c *gin.Context) {
+	m, ok := bindMovie(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	set := bson.M{
+		"title":           m.Title,
+		"runtime_minutes": m.RuntimeMinutes,
+		"age_rating":      m.AgeRating,
+		"genres":          m.Genres,
+		"languages":       m.Languages,
+		"poster_url":      m.PosterURL,
+		"release_date":    m.ReleaseDate,
+		"end_date":        m.EndDate,
+	}
+	if err := h.Repo.UpdateMovie(ctx, id, set); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+	updated, err := h.Repo.GetMovie(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteMovie removes a movie no screening references.
+func (h *Handler) DeleteMovie(c *gin.Context) {
+	err := h.Repo.DeleteMovie(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": "movie has screenings"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "movie not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
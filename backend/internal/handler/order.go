@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// loadOrder fetches an order owned by the caller (admins may read any) and its bookings; it writes the error response.
+func (h *Handler) loadOrder(c *gin.Context) (*model.Order, []*model.Booking, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, nil, false
+	}
+	o, err := h.Repo.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return nil, nil, false
+	}
+	if o.UserID != userID && c.GetString("role") != string(model.RoleAdmin) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your order"})
+		return nil, nil, false
+	}
+	bookings, err := h.Repo.ListBookings(c.Request.Context(), bson.M{"order_id": o.ID.Hex()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return o, bookings, true
+}
+
+// GetOrder returns an order with its line-item bookings.
+func (h *Handler) GetOrder(c *gin.Context) {
+	o, bookings, ok := h.loadOrder(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"order": o, "bookings": bookings})
+}
+
+// ConfirmOrder pays for every PENDING seat of an order in one call. All locks must still be held. If a seat
+// fails to confirm, the seats after it are left PENDING and the response reports what was actually charged.
+func (h *Handler) ConfirmOrder(c *gin.Context) {
+	o, bookings, ok := h.loadOrder(c)
+	if !ok {
+		return
+	}
+	if o.UserID != c.GetString("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your order"})
+		return
+	}
+	if o.Status != model.OrderPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order already " + o.Status})
+		return
+	}
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, o.ScreeningID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	if herr := h.checkOnSale(s); herr != nil {
+		c.JSON(herr.Status, herr.Body)
+		return
+	}
+	var pending []*model.Booking
+	for _, b := range bookings {
+		if b.Status != "PENDING" {
+			continue
+		}
+		lockID, _ := h.Lock.GetLockID(ctx, b.ScreeningID, b.SeatRow, b.SeatCol)
+		if lockID != b.LockID {
+			c.JSON(http.StatusConflict, gin.H{"error": "lock expired", "booking_id": b.ID.Hex()})
+			return
+		}
+		pending = append(pending, b)
+	}
+	if len(pending) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nothing to confirm"})
+		return
+	}
+	var failed *holdError
+	var charged float64
+	confirmed, labels := []string{}, []string{}
+	seats := make([]model.Seat, 0, len(pending))
+	for _, b := range pending {
+		if failed = h.confirmBooking(ctx, s, b); failed != nil {
+			failed.Body["booking_id"] = b.ID.Hex()
+			break
+		}
+		charged += b.PaidAmount
+		confirmed = append(confirmed, b.ID.Hex())
+		labels = append(labels, b.SeatLabel)
+		seat := s.SeatAt(b.SeatRow, b.SeatCol)
+		seat.Status, seat.UserID = model.SeatBooked, b.UserID
+		seats = append(seats, seat)
+	}
+	status, _ := h.Repo.SyncOrderStatus(ctx, o.ID.Hex())
+	if len(seats) > 0 {
+		h.Seats.Publish(ctx, o.ScreeningID, seats...)
+		h.Hub.BroadcastAdmin("REFRESH", nil)
+	}
+	if failed != nil {
+		failed.Body["order_status"] = status
+		failed.Body["confirmed_booking_ids"] = confirmed
+		failed.Body["charged"] = charged
+		c.JSON(failed.Status, failed.Body)
+		return
+	}
+	h.audit(model.EventOrderPaid, map[string]any{"order_id": o.ID.Hex(), "user_id": o.UserID, "screening_id": o.ScreeningID, "seat_labels": labels, "total": charged})
+	c.JSON(http.StatusOK, gin.H{"status": status, "order_id": o.ID.Hex(), "total": charged, "confirmed_booking_ids": confirmed})
+}
+
+// CancelOrder releases every PENDING seat of an unpaid order.
+func (h *Handler) CancelOrder(c *gin.Context) {
+	o, bookings, ok := h.loadOrder(c)
+	if !ok {
+		return
+	}
+	if o.UserID != c.GetString("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your order"})
+		return
+	}
+	if o.Status != model.OrderPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order already " + o.Status})
+		return
+	}
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, o.ScreeningID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	var seats []model.Seat
+	for _, b := range bookings {
+		updated, _ := h.Repo.SetBookingStatusIfPending(ctx, b.ID.Hex(), "CANCELLED")
+		if !updated {
+			continue
+		}
+		_ = h.Lock.Release(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
+		_ = h.Quota.Release(ctx, b.ScreeningID, b.UserID, []lock.Seat{{Row: b.SeatRow, Col: b.SeatCol}})
+		h.audit(model.EventBookingCancelled, map[string]any{"booking_id": b.ID.Hex(), "user_id": b.UserID, "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol, "seat_label": b.SeatLabel})
+		_ = h.Pub.PublishSeatReleased(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel)
+		seats = append(seats, s.SeatAt(b.SeatRow, b.SeatCol))
+	}
+	status, _ := h.Repo.SyncOrderStatus(ctx, o.ID.Hex())
+	if len(seats) > 0 {
+		h.Seats.Publish(ctx, o.ScreeningID, seats...)
+		h.Hub.BroadcastAdmin("REFRESH", nil)
+	}
+	c.JSON(http.StatusOK, gin.H{"status": status})
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"math"
+	"time"
+
+	"cinema-booking/internal/model"
+)
+
+// RefundPolicy decides how much of a confirmed booking is refunded based on time left before the screening.
+type RefundPolicy struct {
+	FullRefundBefore time.Duration // full refund if cancelled at least this long before ScreenAt
+	PartialPercent   int           // refunded after FullRefundBefore but before ScreenAt
+}
+
+// Percent returns the refund percent for cancelling at now, or ok=false once the screening has started.
+func (p RefundPolicy) Percent(screenAt, now time.Time) (percent int, ok bool) {
+	left := screenAt.Sub(now)
+	switch {
+	case left <= 0:
+		return 0, false
+	case left >= p.FullRefundBefore:
+		return 100, true
+	default:
+		return p.PartialPercent, true
+	}
+}
+
+// refundAmount is percent of what was actually paid for b, rounded to 2 decimals.
+// Bookings confirmed before amounts were recorded fall back to the price quoted at lock.
+func refundAmount(b *model.Booking, percent int) float64 {
+	paid := b.PaidAmount
+	if paid == 0 {
+		paid = b.Price
+	}
+	return math.Round(paid*float64(percent)) / 100
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"cinema-booking/internal/model"
+)
+
+func TestRefundPolicyPercent(t *testing.T) {
+	p := RefundPolicy{FullRefundBefore: 24 * time.Hour, PartialPercent: 50}
+	screenAt := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		before  time.Duration // time left until the screening
+		percent int
+		ok      bool
+	}{
+		{"well ahead", 72 * time.Hour, 100, true},
+		{"exactly at the full refund boundary", 24 * time.Hour, 100, true},
+		{"just inside the boundary", 24*time.Hour - time.Second, 50, true},
+		{"shortly before the screening", time.Minute, 50, true},
+		{"screening starts now", 0, 0, false},
+		{"screening already started", -time.Minute, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, ok := p.Percent(screenAt, screenAt.Add(-tt.before))
+			if percent != tt.percent || ok != tt.ok {
+				t.Errorf("Percent() = %d, %v, want %d, %v", percent, ok, tt.percent, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRefundAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		booking model.Booking
+		percent int
+		want    float64
+	}{
+		{"full refund of paid amount", model.Booking{Price: 250, PaidAmount: 200}, 100, 200},
+		{"partial refund of paid amount", model.Booking{Price: 250, PaidAmount: 200}, 50, 100},
+		{"rounded to cents", model.Booking{PaidAmount: 99.99}, 33, 33},
+		{"unpaid amount falls back to price", model.Booking{Price: 180}, 50, 90},
+		{"nothing refunded", model.Booking{Price: 180, PaidAmount: 180}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundAmount(&tt.booking, tt.percent); got != tt.want {
+				t.Errorf("refundAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
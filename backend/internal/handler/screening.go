@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"cinema-booking/internal/model"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -13,6 +18,7 @@ import (
 type SeatLockInfo struct {
 	Row       int       `json:"row"`
 	Col       int       `json:"col"`
+	Label     string    `json:"label,omitempty"`
 	UserID    string    `json:"user_id"`
 	BookingID string    `json:"booking_id,omitempty"`
 	LockedAt  time.Time `json:"locked_at"`
@@ -23,16 +29,25 @@ type SeatLockInfo struct {
 type SeatBookedInfo struct {
 	Row      int        `json:"row"`
 	Col      int        `json:"col"`
+	Label    string     `json:"label,omitempty"`
 	UserID   string     `json:"user_id"`
 	BookedAt *time.Time `json:"booked_at,omitempty"`
 }
 
+// ListScreenings returns all screenings, or those of ?movie_id.
 func (h *Handler) ListScreenings(c *gin.Context) {
-	list, err := h.Repo.ListScreenings(c.Request.Context())
+	filter := bson.M{}
+	if movieID := c.Query("movie_id"); movieID != "" {
+		filter["movie_id"] = movieID
+	}
+	list, err := h.Repo.FindScreenings(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	for _, s := range list {
+		h.withSaleState(s)
+	}
 	c.JSON(http.StatusOK, list)
 }
 
@@ -43,26 +58,67 @@ func (h *Handler) GetScreening(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
 		return
 	}
-	c.JSON(http.StatusOK, s)
+	c.JSON(http.StatusOK, h.withSaleState(s))
 }
 
+// GetSeatMap returns the full seat map with its version. The version is read before the grid is built, so
+// changes racing with the request show up again in the next delta rather than being lost. The ETag is the
+// seat version, the screening revision (prices, layout, labels, time) and the sale state; a matching If-None-Match
+// gets 304.
 func (h *Handler) GetSeatMap(c *gin.Context) {
 	id := c.Param("id")
-	s, err := h.Repo.GetScreening(c.Request.Context(), id)
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
 		return
 	}
-	bookings, _ := h.Repo.ListBookings(c.Request.Context(), map[string]interface{}{"screening_id": id})
-	ctx := c.Request.Context()
-	seats := make([][]model.Seat, s.Rows)
-	for r := 0; r < s.Rows; r++ {
-		seats[r] = make([]model.Seat, s.Cols)
-		for col := 0; col < s.Cols; col++ {
-			seats[r][col] = h.seatState(ctx, id, bookings, r, col)
+	version, err := h.Seats.Current(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.withSaleState(s)
+	etag := fmt.Sprintf(`"%d-%d-%s"`, version, s.Revision, s.SaleState)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	seats := h.seatGrid(ctx, s)
+	c.JSON(http.StatusOK, gin.H{"screening": s, "seats": seats, "version": version})
+}
+
+// GetSeatChanges returns the seats changed since ?since=<version>, one entry per seat in its latest state.
+// 410 RESYNC_REQUIRED means the change log no longer covers that version; re-fetch the full map.
+func (h *Handler) GetSeatChanges(c *gin.Context) {
+	id := c.Param("id")
+	since, err := strconv.ParseInt(c.Query("since"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a seat map version"})
+		return
+	}
+	version, changes, ok, err := h.Seats.Since(c.Request.Context(), id, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusGone, gin.H{"error": "seat map changed too much, re-fetch it", "code": "RESYNC_REQUIRED", "version": version})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"version": version, "since": since, "changes": changes})
+}
+
+func etagMatches(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == etag || t == "*" {
+			return true
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{"screening": s, "seats": seats})
+	return false
 }
 
 // GetSeatDetails returns who locked/booked which seats and when (for listing on ScreeningList).
@@ -74,18 +130,14 @@ func (h *Handler) GetSeatDetails(c *gin.Context) {
 		return
 	}
 	bookings, _ := h.Repo.ListBookings(c.Request.Context(), map[string]interface{}{"screening_id": id})
-	ttlSec := 300
-	if h.LockTTLSeconds > 0 {
-		ttlSec = h.LockTTLSeconds
-	}
-	ttl := time.Duration(ttlSec) * time.Second
+	ttl := h.lockTTL()
 	var locked []SeatLockInfo
 	var booked []SeatBookedInfo
 	ctx := c.Request.Context()
 	for _, b := range bookings {
 		if b.Status == "CONFIRMED" {
 			booked = append(booked, SeatBookedInfo{
-				Row: b.SeatRow, Col: b.SeatCol, UserID: b.UserID,
+				Row: b.SeatRow, Col: b.SeatCol, Label: b.SeatLabel, UserID: b.UserID,
 				BookedAt: b.ConfirmedAt,
 			})
 			if booked[len(booked)-1].BookedAt == nil {
@@ -97,8 +149,11 @@ func (h *Handler) GetSeatDetails(c *gin.Context) {
 			lockID, _ := h.Lock.GetLockID(ctx, id, b.SeatRow, b.SeatCol)
 			if lockID == b.LockID {
 				unlocksAt := b.CreatedAt.Add(ttl)
+				if b.LockExpiresAt != nil {
+					unlocksAt = *b.LockExpiresAt
+				}
 				locked = append(locked, SeatLockInfo{
-					Row: b.SeatRow, Col: b.SeatCol, UserID: b.UserID,
+					Row: b.SeatRow, Col: b.SeatCol, Label: b.SeatLabel, UserID: b.UserID,
 					BookingID: b.ID.Hex(), LockedAt: b.CreatedAt, UnlocksAt: unlocksAt,
 				})
 			}
@@ -113,11 +168,18 @@ func (h *Handler) GetSeatDetails(c *gin.Context) {
 
 func (h *Handler) CreateScreening(c *gin.Context) {
 	var body struct {
-		MovieID   string `json:"movie_id" binding:"required"`
-		MovieName string `json:"movie_name" binding:"required"`
-		ScreenAt  string `json:"screen_at" binding:"required"`
-		Rows      int    `json:"rows" binding:"required,min=1"`
-		Cols      int    `json:"cols" binding:"required,min=1"`
+		MovieID     string               `json:"movie_id" binding:"required"`
+		ScreenAt    string               `json:"screen_at" binding:"required"`
+		HallID      string               `json:"hall_id"`
+		Runtime     int                  `json:"runtime_minutes" binding:"min=0"` // 0: the movie's runtime
+		LayoutID    string               `json:"layout_id"`                       // only without hall_id
+		SeatingRule *model.SeatingRule   `json:"seating_rule"`
+		Price       float64              `json:"price" binding:"min=0"`
+		Categories  []model.SeatCategory `json:"categories"`
+		OnSaleAt    *time.Time           `json:"on_sale_at"`
+		OffSaleAt   *time.Time           `json:"off_sale_at"`
+		WaitingRoom bool                 `json:"waiting_room"`
+		Labeling    *model.SeatLabeling  `json:"labeling"` // only without hall_id
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -128,18 +190,348 @@ func (h *Handler) CreateScreening(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid screen_at"})
 		return
 	}
+	if msg := validateSaleWindow(t, body.OnSaleAt, body.OffSaleAt); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if msg := body.Labeling.Validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	ctx := c.Request.Context()
+	movie, err := h.Repo.GetMovie(ctx, body.MovieID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "movie not found"})
+		return
+	}
+	if body.Runtime == 0 {
+		body.Runtime = movie.RuntimeMinutes
+	}
 	s := &model.Screening{
-		ID:        primitive.NewObjectID(),
-		MovieID:   body.MovieID,
-		MovieName: body.MovieName,
-		ScreenAt:  t,
-		Rows:      body.Rows,
-		Cols:      body.Cols,
-		CreatedAt: time.Now(),
-	}
-	if err := h.Repo.CreateScreening(c.Request.Context(), s); err != nil {
+		ID:             primitive.NewObjectID(),
+		MovieID:        movie.ID.Hex(),
+		MovieName:      movie.Title,
+		ScreenAt:       t,
+		RuntimeMinutes: body.Runtime,
+		CreatedAt:      time.Now(),
+		SeatingRule:    body.SeatingRule,
+		Price:          body.Price,
+		Categories:     body.Categories,
+		OnSaleAt:       body.OnSaleAt,
+		OffSaleAt:      body.OffSaleAt,
+		WaitingRoom:    body.WaitingRoom,
+		Labeling:       body.Labeling,
+	}
+	if body.HallID != "" {
+		if body.LayoutID != "" || body.Labeling != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "layout and labeling come from the hall, do not send them with hall_id"})
+			return
+		}
+		hall, herr := h.hallFor(ctx, body.HallID)
+		if herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		unlock, herr := h.lockHallSchedule(ctx, hall.ID.Hex())
+		if herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		defer unlock()
+		if herr := h.scheduleInHall(ctx, s, hall); herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		body.LayoutID, s.Labeling = hall.LayoutID, hall.Labeling
+	}
+	// Screenings take their floor plan from a layout; bare rows and cols are no longer accepted
+	if body.LayoutID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hall_id or layout_id is required"})
+		return
+	}
+	layout, err := h.Repo.GetLayout(ctx, body.LayoutID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "layout not found"})
+		return
+	}
+	s.LayoutID, s.Layout, s.Rows, s.Cols = layout.ID.Hex(), layout, layout.Rows, layout.Cols
+	if msg := validateSeatPlan(s); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	if err := h.Repo.CreateScreening(ctx, s); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, s)
+	if s.WaitingRoom {
+		if err := h.Room.SetEnabled(ctx, s.ID.Hex(), true); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusCreated, h.withSaleState(s))
+}
+
+// UpdateScreening reschedules a screening (admin): screen_at, runtime_minutes and hall_id may change.
+// The new slot must not overlap another screening in the hall. Moving to a hall with another layout is refused
+// once seats are held or sold, since they would not map onto the new floor plan. Seat labels follow the new hall
+// only together with its layout, so labels already printed on tickets stay valid.
+func (h *Handler) UpdateScreening(c *gin.Context) {
+	var body struct {
+		ScreenAt *time.Time `json:"screen_at"`
+		Runtime  *int       `json:"runtime_minutes"`
+		HallID   *string    `json:"hall_id"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	s, err := h.Repo.GetScreening(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	before := map[string]any{"screen_at": s.ScreenAt, "runtime_minutes": s.RuntimeMinutes, "hall_id": s.HallID}
+	if body.ScreenAt != nil {
+		s.ScreenAt = *body.ScreenAt
+		if msg := validateSaleWindow(s.ScreenAt, s.OnSaleAt, s.OffSaleAt); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+	}
+	if body.Runtime != nil {
+		if *body.Runtime < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "runtime_minutes must not be negative"})
+			return
+		}
+		s.RuntimeMinutes = *body.Runtime
+	}
+	set := bson.M{"screen_at": s.ScreenAt, "runtime_minutes": s.RuntimeMinutes}
+	hallID := s.HallID
+	if body.HallID != nil {
+		hallID = *body.HallID
+	}
+	if hallID != "" {
+		hall, herr := h.hallFor(ctx, hallID)
+		if herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		unlock, herr := h.lockHallSchedule(ctx, hallID)
+		if herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		defer unlock()
+		if hall.LayoutID != s.LayoutID && hallID != s.HallID {
+			active, err := h.Repo.CountBookings(ctx, bson.M{"screening_id": id, "status": bson.M{"$in": []string{"PENDING", "CONFIRMED"}}})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if active > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "seats are already held or sold, cannot move to a hall with another layout"})
+				return
+			}
+			layout, err := h.Repo.GetLayout(ctx, hall.LayoutID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "hall layout not found"})
+				return
+			}
+			s.LayoutID, s.Layout, s.Rows, s.Cols, s.Labeling = layout.ID.Hex(), layout, layout.Rows, layout.Cols, hall.Labeling
+			// Categories and aisles were drawn for the old floor plan
+			if msg := validateSeatPlan(s); msg != "" {
+				c.JSON(http.StatusConflict, gin.H{"error": "screening does not fit the new hall: " + msg})
+				return
+			}
+			set["layout_id"], set["rows"], set["cols"], set["labeling"] = s.LayoutID, s.Rows, s.Cols, s.Labeling
+		}
+		if herr := h.scheduleInHall(ctx, s, hall); herr != nil {
+			c.JSON(herr.Status, herr.Body)
+			return
+		}
+		set["hall_id"], set["cinema_id"], set["occupied_until"] = s.HallID, s.CinemaID, s.OccupiedUntil
+	}
+	if err := h.Repo.UpdateScreening(ctx, id, set); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.withSaleState(s)
+	h.audit(model.EventScreeningUpdated, map[string]any{"screening_id": id, "user_id": c.GetString("user_id"), "before": before, "after": set})
+	payload := map[string]any{"screening_id": id, "movie_name": s.MovieName, "screen_at": s.ScreenAt, "previous_screen_at": before["screen_at"], "hall_id": s.HallID}
+	h.Hub.BroadcastNotification("screening:"+id, model.EventScreeningUpdated, payload)
+	h.notifyHolders(ctx, id, payload)
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, s)
+}
+
+// notifyHolders tells every user holding or owning a seat of the screening that it changed, whether or not they
+// are looking at its seat map.
+func (h *Handler) notifyHolders(ctx context.Context, screeningID string, payload map[string]any) {
+	bookings, err := h.Repo.ListBookings(ctx, bson.M{"screening_id": screeningID, "status": bson.M{"$in": []string{"PENDING", "CONFIRMED"}}})
+	if err != nil {
+		return
+	}
+	seen := map[string]bool{}
+	for _, b := range bookings {
+		if seen[b.UserID] {
+			continue
+		}
+		seen[b.UserID] = true
+		h.Hub.NotifyUser(b.UserID, model.EventScreeningUpdated, payload)
+	}
+}
+
+// lockHallSchedule serializes schedule changes of a hall, so the overlap check in scheduleInHall and the write
+// that follows cannot interleave with another admin's. The caller must call unlock once the screening is stored.
+func (h *Handler) lockHallSchedule(ctx context.Context, hallID string) (unlock func(), herr *holdError) {
+	lockID, err := h.Lock.AcquireMutex(ctx, "hall_schedule:"+hallID, 10*time.Second, 3*time.Second)
+	if err != nil {
+		return nil, &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	if lockID == "" {
+		return nil, &holdError{http.StatusConflict, gin.H{"error": "hall schedule is being changed, try again", "code": "SCHEDULE_BUSY"}}
+	}
+	return func() { _ = h.Lock.ReleaseMutex(context.Background(), "hall_schedule:"+hallID, lockID) }, nil
+}
+
+func (h *Handler) hallFor(ctx context.Context, hallID string) (*model.Hall, *holdError) {
+	hall, err := h.Repo.GetHall(ctx, hallID)
+	if err != nil {
+		return nil, &holdError{http.StatusBadRequest, gin.H{"error": "hall not found"}}
+	}
+	return hall, nil
+}
+
+// scheduleInHall places s in hall, setting its hall, cinema and occupied slot (runtime plus the cleaning buffer).
+// It fails with SCHEDULE_CONFLICT if that slot overlaps another screening in the hall. Callers hold
+// lockHallSchedule until the screening is written.
+func (h *Handler) scheduleInHall(ctx context.Context, s *model.Screening, hall *model.Hall) *holdError {
+	if s.RuntimeMinutes < 1 {
+		return &holdError{http.StatusBadRequest, gin.H{"error": "runtime_minutes is required to schedule in a hall"}}
+	}
+	buffer := h.CleaningBuffer
+	if hall.CleaningMinutes > 0 {
+		buffer = time.Duration(hall.CleaningMinutes) * time.Minute
+	}
+	until := s.ScreenAt.Add(time.Duration(s.RuntimeMinutes)*time.Minute + buffer)
+	other, err := h.Repo.FindOverlappingScreening(ctx, hall.ID.Hex(), s.ScreenAt, until, s.ID.Hex())
+	if err != nil {
+		return &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	if other != nil {
+		return &holdError{http.StatusConflict, gin.H{
+			"error": "hall is already in use at that time",
+			"code":  "SCHEDULE_CONFLICT",
+			"conflict": gin.H{
+				"screening_id":   other.ID.Hex(),
+				"movie_name":     other.MovieName,
+				"screen_at":      other.ScreenAt,
+				"occupied_until": other.OccupiedUntil,
+			},
+		}}
+	}
+	s.HallID, s.CinemaID, s.OccupiedUntil = hall.ID.Hex(), hall.CinemaID, &until
+	return nil
+}
+
+// validateSaleWindow checks that sales open no later than the screening and close after they open.
+func validateSaleWindow(screenAt time.Time, onSaleAt, offSaleAt *time.Time) string {
+	if onSaleAt != nil && onSaleAt.After(screenAt) {
+		return "on_sale_at must not be after screen_at"
+	}
+	if onSaleAt != nil && offSaleAt != nil && !offSaleAt.After(*onSaleAt) {
+		return "off_sale_at must be after on_sale_at"
+	}
+	return ""
+}
+
+// validateSeatPlan checks the seating rule, seat labels and price categories of s against its current Rows and Cols.
+func validateSeatPlan(s *model.Screening) string {
+	if r := s.SeatingRule; r != nil {
+		switch r.OrphanSeat {
+		case model.OrphanRuleOff, model.OrphanRuleWarn, model.OrphanRuleReject:
+		default:
+			return "seating_rule.orphan_seat must be OFF, WARN or REJECT"
+		}
+		for _, c := range r.AisleAfter {
+			if c < 0 || c >= s.Cols-1 {
+				return fmt.Sprintf("seating_rule.aisle_after %d is outside the hall", c)
+			}
+		}
+	}
+	if dup := s.DuplicateLabel(); dup != "" {
+		return "seat label " + dup + " is used by more than one seat"
+	}
+	return validateCategories(s.Categories, s.Rows, s.Cols)
+}
+
+// validateCategories checks codes are unique and every assigned row and seat is inside the hall.
+// A seat may be listed by one category only; whole rows likewise.
+func validateCategories(categories []model.SeatCategory, rows, cols int) string {
+	codes := map[string]bool{}
+	rowTaken := map[int]bool{}
+	seatTaken := map[model.SeatRef]bool{}
+	for _, cat := range categories {
+		if cat.Code == "" {
+			return "category code is required"
+		}
+		if codes[cat.Code] {
+			return "duplicate category " + cat.Code
+		}
+		codes[cat.Code] = true
+		if cat.Price < 0 {
+			return "category " + cat.Code + " has a negative price"
+		}
+		for _, r := range cat.Rows {
+			if r < 0 || r >= rows {
+				return fmt.Sprintf("category %s: row %d out of range", cat.Code, r)
+			}
+			if rowTaken[r] {
+				return fmt.Sprintf("row %d is in more than one category", r)
+			}
+			rowTaken[r] = true
+		}
+		for _, ref := range cat.Seats {
+			if ref.Row < 0 || ref.Row >= rows || ref.Col < 0 || ref.Col >= cols {
+				return fmt.Sprintf("category %s: seat %d:%d out of range", cat.Code, ref.Row, ref.Col)
+			}
+			if seatTaken[ref] {
+				return fmt.Sprintf("seat %d:%d is in more than one category", ref.Row, ref.Col)
+			}
+			seatTaken[ref] = true
+		}
+	}
+	return ""
+}
+
+// PauseSales stops seat sales on a screening immediately (admin).
+func (h *Handler) PauseSales(c *gin.Context) { h.setSalesPaused(c, true) }
+
+// ResumeSales lifts an admin pause; the on-sale window still applies.
+func (h *Handler) ResumeSales(c *gin.Context) { h.setSalesPaused(c, false) }
+
+func (h *Handler) setSalesPaused(c *gin.Context, paused bool) {
+	id := c.Param("id")
+	if err := h.Repo.SetSalesPaused(c.Request.Context(), id, paused); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	s, err := h.Repo.GetScreening(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	event := model.EventSalesResumed
+	if paused {
+		event = model.EventSalesPaused
+	}
+	h.withSaleState(s)
+	payload := map[string]any{"screening_id": id, "user_id": c.GetString("user_id"), "sale_state": s.SaleState}
+	h.audit(event, payload)
+	h.Hub.BroadcastNotification("screening:"+id, event, payload)
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, s)
 }
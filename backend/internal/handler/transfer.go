@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"cinema-booking/internal/model"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// transferOfferTTL is how long a transfer offer can be accepted (defaults to 48 hours).
+func (h *Handler) transferOfferTTL() time.Duration {
+	if h.TransferOfferTTL > 0 {
+		return h.TransferOfferTTL
+	}
+	return 48 * time.Hour
+}
+
+// transferPayload is the audit and notification payload of a transfer event.
+func transferPayload(b *model.Booking, fromUserID, toUserID string) map[string]any {
+	return map[string]any{
+		"booking_id":   b.ID.Hex(),
+		"screening_id": b.ScreeningID,
+		"from_user_id": fromUserID,
+		"to_user_id":   toUserID,
+		"seat_row":     b.SeatRow,
+		"seat_col":     b.SeatCol,
+		"seat_label":   b.SeatLabel,
+	}
+}
+
+// OfferTransfer offers a CONFIRMED booking to another registered user by email; ownership moves when they accept.
+// The offer lapses after TransferOfferTTL and replaces any earlier offer of the same booking.
+func (h *Handler) OfferTransfer(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var body struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	b, err := h.Repo.GetBookingByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if b.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your booking"})
+		return
+	}
+	if b.Status != "CONFIRMED" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only confirmed bookings can be transferred"})
+		return
+	}
+	to, err := h.Repo.GetUserByEmail(ctx, strings.TrimSpace(strings.ToLower(body.Email)))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recipient not found"})
+		return
+	}
+	if to.ID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot transfer to yourself"})
+		return
+	}
+	until := time.Now().Add(h.transferOfferTTL())
+	ok, err := h.Repo.OfferTransfer(ctx, b.ID.Hex(), userID, to.ID, until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "booking changed, try again"})
+		return
+	}
+	if b.TransferTo != "" && b.TransferTo != to.ID {
+		h.notifyTransferRevoked(b, "replaced")
+	}
+	payload := transferPayload(b, userID, to.ID)
+	payload["expires_at"] = until
+	h.audit(model.EventTransferOffered, payload)
+	// Recipients without an open socket find the offer in GET /me/transfers
+	h.Hub.NotifyUser(to.ID, model.EventTransferOffered, payload)
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, gin.H{"status": "offered", "to_user_id": to.ID, "expires_at": until})
+}
+
+// RevokeTransfer withdraws the owner's pending transfer offer of a booking.
+func (h *Handler) RevokeTransfer(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ctx := c.Request.Context()
+	b, err := h.Repo.GetBookingByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if b.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not your booking"})
+		return
+	}
+	ok, err := h.Repo.RevokeTransfer(ctx, b.ID.Hex(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok || b.TransferTo == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no transfer offered"})
+		return
+	}
+	h.notifyTransferRevoked(b, "revoked")
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
+
+// notifyTransferRevoked tells the recipient of b's transfer offer (if any) that it is gone and records why.
+func (h *Handler) notifyTransferRevoked(b *model.Booking, reason string) {
+	if b.TransferTo == "" {
+		return
+	}
+	payload := transferPayload(b, b.UserID, b.TransferTo)
+	payload["reason"] = reason
+	h.audit(model.EventTransferRevoked, payload)
+	h.Hub.NotifyUser(b.TransferTo, model.EventTransferRevoked, payload)
+}
+
+// ListIncomingTransfers lists the transfer offers made to the caller that can still be accepted.
+func (h *Handler) ListIncomingTransfers(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	list, err := h.Repo.ListBookings(c.Request.Context(), bson.M{"transfer_to": userID, "status": "CONFIRMED", "transfer_until": bson.M{"$gt": time.Now()}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if list == nil {
+		list = []*model.Booking{}
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// AcceptTransfer lets the recipient of a transfer offer take ownership of the booking.
+func (h *Handler) AcceptTransfer(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ctx := c.Request.Context()
+	b, err := h.Repo.GetBookingByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	if b.TransferTo != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "no transfer offered to you"})
+		return
+	}
+	if b.Status != "CONFIRMED" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "booking already " + b.Status})
+		return
+	}
+	if b.TransferUntil == nil || !time.Now().Before(*b.TransferUntil) {
+		c.JSON(http.StatusGone, gin.H{"error": "transfer offer expired"})
+		return
+	}
+	ok, err := h.Repo.AcceptTransfer(ctx, b.ID.Hex(), b.UserID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "transfer offer no longer valid"})
+		return
+	}
+	payload := transferPayload(b, b.UserID, userID)
+	h.audit(model.EventTicketTransferred, payload)
+	h.Hub.NotifyUser(userID, model.EventTicketTransferred, payload)
+	h.Hub.NotifyUser(b.UserID, model.EventTicketTransferred, payload)
+	// Seat now shows the new owner
+	h.broadcastSeat(ctx, b.ScreeningID, b.SeatRow, b.SeatCol)
+	c.JSON(http.StatusOK, gin.H{"status": "transferred"})
+}
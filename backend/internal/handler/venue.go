@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/repository"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ListCinemas returns all cinemas.
+func (h *Handler) ListCinemas(c *gin.Context) {
+	list, err := h.Repo.ListCinemas(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetCinema returns a cinema with its halls.
+func (h *Handler) GetCinema(c *gin.Context) {
+	ctx := c.Request.Context()
+	cinema, err := h.Repo.GetCinema(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cinema not found"})
+		return
+	}
+	halls, err := h.Repo.ListHalls(ctx, cinema.ID.Hex())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"cinema": cinema, "halls": halls})
+}
+
+type cinemaBody struct {
+	Name    string `json:"name" binding:"required"`
+	City    string `json:"city"`
+	Address string `json:"address"`
+}
+
+func (h *Handler) CreateCinema(c *gin.Context) {
+	var body cinemaBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cinema := &model.Cinema{Name: strings.TrimSpace(body.Name), City: body.City, Address: body.Address}
+	if err := h.Repo.CreateCinema(c.Request.Context(), cinema); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cinema)
+}
+
+func (h *Handler) UpdateCinema(c *gin.Context) {
+	var body cinemaBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	set := bson.M{"name": strings.TrimSpace(body.Name), "city": body.City, "address": body.Address}
+	if err := h.Repo.UpdateCinema(ctx, id, set); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cinema not found"})
+		return
+	}
+	cinema, err := h.Repo.GetCinema(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cinema not found"})
+		return
+	}
+	c.JSON(http.StatusOK, cinema)
+}
+
+// DeleteCinema removes a cinema that has no halls left.
+func (h *Handler) DeleteCinema(c *gin.Context) {
+	err := h.Repo.DeleteCinema(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": "cinema still has halls"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cinema not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// ListHalls returns all halls, or those of ?cinema_id.
+func (h *Handler) ListHalls(c *gin.Context) {
+	list, err := h.Repo.ListHalls(c.Request.Context(), c.Query("cinema_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) GetHall(c *gin.Context) {
+	hall, err := h.Repo.GetHall(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hall not found"})
+		return
+	}
+	c.JSON(http.StatusOK, hall)
+}
+
+type hallBody struct {
+	CinemaID        string              `json:"cinema_id" binding:"required"`
+	Name            string              `json:"name" binding:"required"`
+	LayoutID        string              `json:"layout_id" binding:"required"`
+	CleaningMinutes int                 `json:"cleaning_minutes" binding:"min=0"`
+	Labeling        *model.SeatLabeling `json:"labeling"`
+}
+
+// validateHall checks the hall's seat labeling, that the cinema and layout it refers to exist and that the
+// labeling gives every seat of the layout a unique label; it writes the error response.
+func (h *Handler) validateHall(c *gin.Context, body hallBody) bool {
+	if msg := body.Labeling.Validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return false
+	}
+	ctx := c.Request.Context()
+	if _, err := h.Repo.GetCinema(ctx, body.CinemaID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cinema not found"})
+		return false
+	}
+	layout, err := h.Repo.GetLayout(ctx, body.LayoutID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "layout not found"})
+		return false
+	}
+	plan := &model.Screening{Rows: layout.Rows, Cols: layout.Cols, Layout: layout, Labeling: body.Labeling}
+	if dup := plan.DuplicateLabel(); dup != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "seat label " + dup + " is used by more than one seat in this layout"})
+		return false
+	}
+	return true
+}
+
+func (h *Handler) CreateHall(c *gin.Context) {
+	var body hallBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.validateHall(c, body) {
+		return
+	}
+	hall := &model.Hall{CinemaID: body.CinemaID, Name: strings.TrimSpace(body.Name), LayoutID: body.LayoutID, CleaningMinutes: body.CleaningMinutes, Labeling: body.Labeling}
+	if err := h.Repo.CreateHall(c.Request.Context(), hall); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, hall)
+}
+
+// UpdateHall changes a hall. Screenings already scheduled keep the layout, seat labels and occupied slot they were
+// created with.
+func (h *Handler) UpdateHall(c *gin.Context) {
+	var body hallBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.validateHall(c, body) {
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	set := bson.M{"cinema_id": body.CinemaID, "name": strings.TrimSpace(body.Name), "layout_id": body.LayoutID, "cleaning_minutes": body.CleaningMinutes, "labeling": body.Labeling}
+	if err := h.Repo.UpdateHall(ctx, id, set); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hall not found"})
+		return
+	}
+	hall, err := h.Repo.GetHall(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hall not found"})
+		return
+	}
+	c.JSON(http.StatusOK, hall)
+}
+
+// DeleteHall removes a hall with no screenings.
+func (h *Handler) DeleteHall(c *gin.Context) {
+	err := h.Repo.DeleteHall(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": "hall has screenings"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hall not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"cinema-booking/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// JoinWaitlist puts the user in line for a sold-out screening; released seats are offered in join order.
+func (h *Handler) JoinWaitlist(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	for _, row := range h.seatGrid(ctx, s) {
+		for _, seat := range row {
+			if seat.Status == model.SeatAvailable {
+				c.JSON(http.StatusConflict, gin.H{"error": "screening has free seats"})
+				return
+			}
+		}
+	}
+	pos, err := h.Waitlist.Join(ctx, s.ID.Hex(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"position": pos})
+}
+
+// WaitlistPosition returns the user's 1-based position in the screening's waitlist (0 if not waiting).
+func (h *Handler) WaitlistPosition(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	pos, err := h.Waitlist.Position(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"position": pos})
+}
+
+func (h *Handler) LeaveWaitlist(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	if err := h.Waitlist.Leave(c.Request.Context(), c.Param("id"), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "left"})
+}
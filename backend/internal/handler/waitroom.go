@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+
+	"cinema-booking/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// AdmissionHeader carries the waiting-room admission token on seat map and lock requests.
+const AdmissionHeader = "X-Admission-Token"
+
+// RequireAdmission rejects requests for a screening with an active waiting room unless the caller presents a
+// live admission token. Admins are not queued. The flag is read from Redis so the check costs no Mongo read.
+func (h *Handler) RequireAdmission() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if herr := h.checkAdmission(c, c.Param("id")); herr != nil {
+			c.AbortWithStatusJSON(herr.Status, herr.Body)
+			return
+		}
+		c.Next()
+	}
+}
+
+// checkAdmission is RequireAdmission for handlers whose route carries no screening id, such as a seat
+// exchange or a group claim; they check the screening they resolved from the booking or group.
+func (h *Handler) checkAdmission(c *gin.Context, screeningID string) *holdError {
+	if c.GetString("role") == string(model.RoleAdmin) {
+		return nil
+	}
+	ctx := c.Request.Context()
+	enabled, err := h.Room.Enabled(ctx, screeningID)
+	if err != nil {
+		return &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	if !enabled {
+		return nil // the handler reports a missing screening
+	}
+	userID := c.GetString("user_id")
+	ok, err := h.Room.Admitted(ctx, screeningID, userID, c.GetHeader(AdmissionHeader))
+	if err != nil {
+		return &holdError{http.StatusInternalServerError, gin.H{"error": err.Error()}}
+	}
+	if !ok {
+		st, _ := h.Room.Status(ctx, screeningID, userID)
+		return &holdError{http.StatusForbidden, gin.H{"error": "waiting room admission required", "code": "ADMISSION_REQUIRED", "queue": st}}
+	}
+	return nil
+}
+
+// JoinQueue enters the screening's waiting room; the response carries the position or, once admitted, the token.
+func (h *Handler) JoinQueue(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	ctx := c.Request.Context()
+	s, err := h.Repo.GetScreening(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	if !s.WaitingRoom {
+		c.JSON(http.StatusConflict, gin.H{"error": "screening has no waiting room"})
+		return
+	}
+	st, err := h.Room.Join(ctx, s.ID.Hex(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, st)
+}
+
+// QueueStatus returns the user's waiting-room state for polling clients; WebSocket clients also get
+// QUEUE_POSITION and QUEUE_ADMITTED notifications.
+func (h *Handler) QueueStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	st, err := h.Room.Status(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, st)
+}
+
+func (h *Handler) LeaveQueue(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	if err := h.Room.Leave(c.Request.Context(), c.Param("id"), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "left"})
+}
+
+// SetWaitingRoom turns the waiting room of a screening on or off (admin).
+func (h *Handler) SetWaitingRoom(c *gin.Context) {
+	var body struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if err := h.Repo.SetWaitingRoom(ctx, id, body.Enabled); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	if err := h.Room.SetEnabled(ctx, id, body.Enabled); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s, err := h.Repo.GetScreening(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "screening not found"})
+		return
+	}
+	h.Hub.BroadcastAdmin("REFRESH", nil)
+	c.JSON(http.StatusOK, h.withSaleState(s))
+}
@@ -0,0 +1,49 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// mutexPrefix keeps named mutexes apart from seat locks, which ListLocks and WatchExpired scan by prefix.
+const mutexPrefix = "mutex:"
+
+// AcquireMutex takes a named lock for a short critical section outside seat locking (e.g. a hall's schedule),
+// retrying for up to wait. ttl bounds how long a crashed holder can block others. Returns an empty lockID if the
+// mutex stayed busy.
+func (m *Manager) AcquireMutex(ctx context.Context, name string, ttl, wait time.Duration) (string, error) {
+	lockID := uuid.New().String()
+	deadline := time.Now().Add(wait)
+	for {
+		ok, err := m.client.SetNX(ctx, mutexPrefix+name, lockID, ttl).Result()
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return lockID, nil
+		}
+		if time.Now().After(deadline) {
+			return "", nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// ReleaseMutex releases a named lock if it is still held by lockID.
+func (m *Manager) ReleaseMutex(ctx context.Context, name, lockID string) error {
+	script := redis.NewScript(`
+		if redis.call("get", KEYS[1]) == ARGV[1] then
+			return redis.call("del", KEYS[1])
+		else
+			return 0
+		end
+	`)
+	return script.Run(ctx, m.client, []string{mutexPrefix + name}, lockID).Err()
+}
@@ -3,6 +3,9 @@ package lock
 import (
 	"context"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +14,12 @@ import (
 
 const keyPrefix = "seat_lock:"
 
+// Seat identifies one seat of a screening by zero-based row/col.
+type Seat struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
 type Manager struct {
 	client *redis.Client
 	ttl    time.Duration
@@ -26,9 +35,14 @@ func (m *Manager) key(screeningID string, row, col int) string {
 
 // Acquire tries to acquire a distributed lock for a seat. Returns lockID if successful, empty string if already locked.
 func (m *Manager) Acquire(ctx context.Context, screeningID string, row, col int) (lockID string, err error) {
+	return m.AcquireFor(ctx, screeningID, row, col, m.ttl)
+}
+
+// AcquireFor is Acquire with a TTL other than the manager default (e.g. waitlist offers).
+func (m *Manager) AcquireFor(ctx context.Context, screeningID string, row, col int, ttl time.Duration) (lockID string, err error) {
 	lockID = uuid.New().String()
 	k := m.key(screeningID, row, col)
-	ok, err := m.client.SetNX(ctx, k, lockID, m.ttl).Result()
+	ok, err := m.client.SetNX(ctx, k, lockID, ttl).Result()
 	if err != nil {
 		return "", err
 	}
@@ -38,6 +52,64 @@ func (m *Manager) Acquire(ctx context.Context, screeningID string, row, col int)
 	return lockID, nil
 }
 
+// AcquireMany locks every seat in seats under a single lockID, all-or-nothing (one Lua script over all keys).
+// Returns empty lockID if any of the seats is already locked; in that case no key is written.
+func (m *Manager) AcquireMany(ctx context.Context, screeningID string, seats []Seat) (lockID string, err error) {
+	return m.AcquireManyFor(ctx, screeningID, seats, m.ttl)
+}
+
+// AcquireManyFor is AcquireMany with a TTL other than the manager default (e.g. group holds).
+func (m *Manager) AcquireManyFor(ctx context.Context, screeningID string, seats []Seat, ttl time.Duration) (lockID string, err error) {
+	if len(seats) == 0 {
+		return "", nil
+	}
+	keys := make([]string, len(seats))
+	for i, s := range seats {
+		keys[i] = m.key(screeningID, s.Row, s.Col)
+	}
+	lockID = uuid.New().String()
+	script := redis.NewScript(`
+		for i = 1, #KEYS do
+			if redis.call("exists", KEYS[i]) == 1 then
+				return 0
+			end
+		end
+		for i = 1, #KEYS do
+			redis.call("set", KEYS[i], ARGV[1], "PX", ARGV[2])
+		end
+		return 1
+	`)
+	result, err := script.Run(ctx, m.client, keys, lockID, ttl.Milliseconds()).Int()
+	if err != nil {
+		return "", err
+	}
+	if result != 1 {
+		return "", nil
+	}
+	return lockID, nil
+}
+
+// ReleaseMany releases every seat in seats that is still held by lockID.
+func (m *Manager) ReleaseMany(ctx context.Context, screeningID string, seats []Seat, lockID string) error {
+	if len(seats) == 0 {
+		return nil
+	}
+	keys := make([]string, len(seats))
+	for i, s := range seats {
+		keys[i] = m.key(screeningID, s.Row, s.Col)
+	}
+	script := redis.NewScript(`
+		local n = 0
+		for i = 1, #KEYS do
+			if redis.call("get", KEYS[i]) == ARGV[1] then
+				n = n + redis.call("del", KEYS[i])
+			end
+		end
+		return n
+	`)
+	return script.Run(ctx, m.client, keys, lockID).Err()
+}
+
 // Release releases the lock only if it's still held by the same lockID (ownership check).
 func (m *Manager) Release(ctx context.Context, screeningID string, row, col int, lockID string) error {
 	k := m.key(screeningID, row, col)
@@ -78,3 +150,122 @@ func (m *Manager) GetLockID(ctx context.Context, screeningID string, row, col in
 	}
 	return val, err
 }
+
+// GetLockIDs returns the current lock holder of each given seat in one MGET; unlocked seats are absent.
+func (m *Manager) GetLockIDs(ctx context.Context, screeningID string, seats []Seat) (map[Seat]string, error) {
+	out := make(map[Seat]string, len(seats))
+	if len(seats) == 0 {
+		return out, nil
+	}
+	keys := make([]string, len(seats))
+	for i, s := range seats {
+		keys[i] = m.key(screeningID, s.Row, s.Col)
+	}
+	vals, err := m.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return out, err
+	}
+	for i, v := range vals {
+		if id, ok := v.(string); ok {
+			out[seats[i]] = id
+		}
+	}
+	return out, nil
+}
+
+// Restore re-creates a seat lock with a known lockID (e.g. after Redis lost it) if the seat is free.
+// Returns true if the key was written.
+func (m *Manager) Restore(ctx context.Context, screeningID string, row, col int, lockID string, ttl time.Duration) (bool, error) {
+	return m.client.SetNX(ctx, m.key(screeningID, row, col), lockID, ttl).Result()
+}
+
+// HeldLock is one seat_lock key currently in Redis.
+type HeldLock struct {
+	ScreeningID string
+	Seat        Seat
+	LockID      string
+}
+
+// ListLocks scans all seat_lock keys. Keys that expire during the scan are skipped.
+func (m *Manager) ListLocks(ctx context.Context) ([]HeldLock, error) {
+	var out []HeldLock
+	iter := m.client.Scan(ctx, 0, keyPrefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		k := iter.Val()
+		screeningID, seat, ok := parseKey(k)
+		if !ok {
+			continue
+		}
+		val, err := m.client.Get(ctx, k).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, HeldLock{ScreeningID: screeningID, Seat: seat, LockID: val})
+	}
+	return out, iter.Err()
+}
+
+// parseKey splits "seat_lock:{screeningID}:{row}:{col}".
+func parseKey(k string) (screeningID string, seat Seat, ok bool) {
+	if !strings.HasPrefix(k, keyPrefix) {
+		return "", Seat{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(k, keyPrefix), ":")
+	if len(parts) != 3 {
+		return "", Seat{}, false
+	}
+	row, err1 := strconv.Atoi(parts[1])
+	col, err2 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil {
+		return "", Seat{}, false
+	}
+	return parts[0], Seat{Row: row, Col: col}, true
+}
+
+// WatchExpired calls onExpired for every seat lock that Redis expires (TTL reached), using keyspace
+// notifications. It turns on "Ex" events with CONFIG SET when allowed; otherwise the server must be started
+// with notify-keyspace-events including E and x. Blocks until ctx is done.
+func (m *Manager) WatchExpired(ctx context.Context, onExpired func(screeningID string, seat Seat)) {
+	if err := m.enableExpiredEvents(ctx); err != nil {
+		log.Printf("lock: enable keyspace events: %v (set notify-keyspace-events Ex on the server)", err)
+	}
+	pubsub := m.client.PSubscribe(ctx, "__keyevent@*__:expired")
+	defer pubsub.Close()
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if screeningID, seat, ok := parseKey(msg.Payload); ok {
+				onExpired(screeningID, seat)
+			}
+		}
+	}
+}
+
+// enableExpiredEvents adds E (keyevent) and x (expired) to notify-keyspace-events, keeping existing flags.
+func (m *Manager) enableExpiredEvents(ctx context.Context) error {
+	cur, err := m.client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return err
+	}
+	flags := cur["notify-keyspace-events"]
+	want := flags
+	if !strings.Contains(want, "E") {
+		want += "E"
+	}
+	if !strings.Contains(want, "x") && !strings.Contains(want, "A") {
+		want += "x"
+	}
+	if want == flags {
+		return nil
+	}
+	return m.client.ConfigSet(ctx, "notify-keyspace-events", want).Err()
+}
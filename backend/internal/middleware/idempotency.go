@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	idempotencyHeader    = "Idempotency-Key"
+	idempotencyKeyPrefix = "idempotency:"
+	// idempotencyInFlightTTL bounds how long a crashed request can block retries with the same key.
+	idempotencyInFlightTTL = 60 * time.Second
+)
+
+type idempotentResponse struct {
+	Done        bool   `json:"done"`
+	Route       string `json:"route"`
+	BodyHash    string `json:"body_hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+type bodyRecorder struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency stores the first response for an Idempotency-Key header (per user) in Redis for retention
+// and replays it verbatim for later requests with the same key. Requests without the header pass through.
+// A key reused for another route or a different request body is refused with 422.
+// Must run after Auth. 5xx responses are not stored so the client can retry.
+func Idempotency(client *redis.Client, retention time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		rk := idempotencyKeyPrefix + c.GetString("user_id") + ":" + key
+		route := c.Request.Method + " " + c.Request.URL.Path
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "cannot read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		bodyHash := hex.EncodeToString(sum[:])
+		pending, _ := json.Marshal(idempotentResponse{Route: route, BodyHash: bodyHash})
+		ok, err := client.SetNX(ctx, rk, pending, idempotencyInFlightTTL).Result()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "idempotency check failed"})
+			return
+		}
+		if !ok {
+			raw, err := client.Get(ctx, rk).Bytes()
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this Idempotency-Key is in progress", "code": "IDEMPOTENCY_IN_PROGRESS"})
+				return
+			}
+			var prev idempotentResponse
+			_ = json.Unmarshal(raw, &prev)
+			if prev.Route != route || prev.BodyHash != bodyHash {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key already used for another request", "code": "IDEMPOTENCY_KEY_REUSED"})
+				return
+			}
+			if !prev.Done {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this Idempotency-Key is in progress", "code": "IDEMPOTENCY_IN_PROGRESS"})
+				return
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(prev.Status, prev.ContentType, prev.Body)
+			c.Abort()
+			return
+		}
+
+		rec := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+
+		// The client may have gone away; the marker must still be replaced or cleared
+		ctx = context.WithoutCancel(ctx)
+		status := rec.Status()
+		if status >= http.StatusInternalServerError {
+			client.Del(ctx, rk)
+			return
+		}
+		done, _ := json.Marshal(idempotentResponse{
+			Done:        true,
+			Route:       route,
+			BodyHash:    bodyHash,
+			Status:      status,
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.buf.Bytes(),
+		})
+		if err := client.Set(ctx, rk, done, retention).Err(); err != nil {
+			// Without the stored response a retry would run again; that beats leaving it stuck on the in-flight marker
+			log.Printf("idempotency: store %s: %v", rk, err)
+			client.Del(ctx, rk)
+		}
+	}
+}
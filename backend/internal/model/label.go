@@ -0,0 +1,150 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Seat numbering directions.
+const (
+	NumberLeftToRight = "LTR" // seat 1 is in column 0
+	NumberRightToLeft = "RTL" // seat 1 is in the last column
+)
+
+// SeatLabeling is how a hall names its seats: a row name followed by the seat number, e.g. "B12".
+// Rows are lettered from the front (row 0) unless RowNames are given; after the last letter names continue
+// with two letters (AA, AB, ...). Only bookable cells are numbered, so aisles and gaps do not use up numbers.
+// A label set on a layout cell overrides the generated one.
+type SeatLabeling struct {
+	RowNames    []string `bson:"row_names,omitempty" json:"row_names,omitempty"`       // explicit names, front to back; rows beyond fall back to letters
+	SkipLetters string   `bson:"skip_letters,omitempty" json:"skip_letters,omitempty"` // letters not used for rows, e.g. "IO"
+	Direction   string   `bson:"direction,omitempty" json:"direction,omitempty"`       // LTR (default) or RTL
+}
+
+// Validate returns a message describing what is wrong with the labeling, or "" if it is usable.
+func (l *SeatLabeling) Validate() string {
+	if l == nil {
+		return ""
+	}
+	if l.Direction != "" && l.Direction != NumberLeftToRight && l.Direction != NumberRightToLeft {
+		return "labeling.direction must be LTR or RTL"
+	}
+	if len(l.letters()) == 0 {
+		return "labeling.skip_letters leaves no letters for rows"
+	}
+	seen := make(map[string]bool, len(l.RowNames))
+	for _, name := range l.RowNames {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			return "labeling.row_names must not be empty"
+		}
+		if seen[name] {
+			return "labeling.row_names has duplicate " + name
+		}
+		seen[name] = true
+	}
+	return ""
+}
+
+// letters returns the alphabet used for row names.
+func (l *SeatLabeling) letters() []byte {
+	var skip string
+	if l != nil {
+		skip = strings.ToUpper(l.SkipLetters)
+	}
+	out := make([]byte, 0, 26)
+	for c := byte('A'); c <= 'Z'; c++ {
+		if strings.IndexByte(skip, c) < 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// RowName returns the name of a row (0 is the front).
+func (l *SeatLabeling) RowName(row int) string {
+	if row < 0 {
+		return ""
+	}
+	if l != nil && row < len(l.RowNames) {
+		return strings.TrimSpace(l.RowNames[row])
+	}
+	letters := l.letters()
+	n := len(letters)
+	if n == 0 {
+		return strconv.Itoa(row + 1)
+	}
+	// Bijective base n: A..Z, AA..AZ, BA..
+	var name []byte
+	for i := row + 1; i > 0; i = (i - 1) / n {
+		name = append([]byte{letters[(i-1)%n]}, name...)
+	}
+	return string(name)
+}
+
+func (l *SeatLabeling) rightToLeft() bool {
+	return l != nil && l.Direction == NumberRightToLeft
+}
+
+// RowSeats returns every cell of a row as SeatAt would, with labels numbered in one pass over the row.
+func (s *Screening) RowSeats(row int) []Seat {
+	out := make([]Seat, s.Cols)
+	for col := range out {
+		out[col] = s.seatAt(row, col)
+	}
+	number := 0
+	for i := range out {
+		col := i
+		if s.Labeling.rightToLeft() {
+			col = s.Cols - 1 - i
+		}
+		if out[col].Status == SeatNone {
+			continue
+		}
+		number++
+		if out[col].Label == "" {
+			out[col].Label = s.Labeling.RowName(row) + strconv.Itoa(number)
+		}
+	}
+	return out
+}
+
+// SeatLabel returns the label of a seat, or "" if there is no seat at row, col.
+func (s *Screening) SeatLabel(row, col int) string {
+	return s.SeatAt(row, col).Label
+}
+
+// DuplicateLabel returns a label given to more than one seat (ignoring case), or "" if every label is unique.
+// Generated labels can collide with custom layout labels or with each other when row names end in digits.
+func (s *Screening) DuplicateLabel() string {
+	seen := make(map[string]bool, s.Rows*s.Cols)
+	for r := 0; r < s.Rows; r++ {
+		for _, seat := range s.RowSeats(r) {
+			if seat.Status == SeatNone {
+				continue
+			}
+			key := strings.ToUpper(seat.Label)
+			if seen[key] {
+				return seat.Label
+			}
+			seen[key] = true
+		}
+	}
+	return ""
+}
+
+// SeatByLabel finds the seat with the given label, ignoring case and surrounding spaces.
+func (s *Screening) SeatByLabel(label string) (row, col int, ok bool) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return 0, 0, false
+	}
+	for r := 0; r < s.Rows; r++ {
+		for _, seat := range s.RowSeats(r) {
+			if seat.Status != SeatNone && strings.EqualFold(seat.Label, label) {
+				return seat.Row, seat.Col, true
+			}
+		}
+	}
+	return 0, 0, false
+}
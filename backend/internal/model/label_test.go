@@ -0,0 +1,113 @@
+package model
+
+import "testing"
+
+func TestRowName(t *testing.T) {
+	tests := []struct {
+		name     string
+		labeling *SeatLabeling
+		row      int
+		want     string
+	}{
+		{"first row", nil, 0, "A"},
+		{"last letter", nil, 25, "Z"},
+		{"after Z", nil, 26, "AA"},
+		{"second double", nil, 27, "AB"},
+		{"after AZ", nil, 52, "BA"},
+		{"negative", nil, -1, ""},
+		{"skips I", &SeatLabeling{SkipLetters: "IO"}, 8, "J"},
+		{"skips O", &SeatLabeling{SkipLetters: "io"}, 13, "P"},
+		{"last letter without I/O", &SeatLabeling{SkipLetters: "IO"}, 23, "Z"},
+		{"after Z without I/O", &SeatLabeling{SkipLetters: "IO"}, 24, "AA"},
+		{"explicit name", &SeatLabeling{RowNames: []string{" AA ", "BB"}}, 1, "BB"},
+		{"past explicit names", &SeatLabeling{RowNames: []string{"AA", "BB"}}, 2, "C"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.labeling.RowName(tt.row); got != tt.want {
+				t.Errorf("RowName(%d) = %q, want %q", tt.row, got, tt.want)
+			}
+		})
+	}
+}
+
+// gapScreening is one row of five cells with an aisle in the middle.
+func gapScreening(labeling *SeatLabeling, cells ...LayoutCell) *Screening {
+	cells = append([]LayoutCell{{Row: 0, Col: 2, Kind: CellAisle}}, cells...)
+	return &Screening{
+		Rows:     1,
+		Cols:     5,
+		Layout:   &Layout{Rows: 1, Cols: 5, Cells: cells},
+		Labeling: labeling,
+	}
+}
+
+func TestRowSeats(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *Screening
+		want []string
+	}{
+		{"left to right", gapScreening(nil), []string{"A1", "A2", "", "A3", "A4"}},
+		{"right to left", gapScreening(&SeatLabeling{Direction: NumberRightToLeft}), []string{"A4", "A3", "", "A2", "A1"}},
+		{"custom label", gapScreening(nil, LayoutCell{Row: 0, Col: 4, Label: "VIP"}), []string{"A1", "A2", "", "A3", "VIP"}},
+		{"custom label keeps numbering", gapScreening(nil, LayoutCell{Row: 0, Col: 0, Label: "X"}), []string{"X", "A2", "", "A3", "A4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seats := tt.s.RowSeats(0)
+			if len(seats) != len(tt.want) {
+				t.Fatalf("got %d seats, want %d", len(seats), len(tt.want))
+			}
+			for col, seat := range seats {
+				if seat.Label != tt.want[col] {
+					t.Errorf("col %d: label %q, want %q", col, seat.Label, tt.want[col])
+				}
+			}
+		})
+	}
+}
+
+func TestSeatByLabel(t *testing.T) {
+	s := &Screening{Rows: 3, Cols: 4}
+	tests := []struct {
+		label    string
+		row, col int
+		ok       bool
+	}{
+		{"B2", 1, 1, true},
+		{"b2", 1, 1, true},
+		{"  c4 ", 2, 3, true},
+		{"", 0, 0, false},
+		{"   ", 0, 0, false},
+		{"D1", 0, 0, false},
+		{"A5", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			row, col, ok := s.SeatByLabel(tt.label)
+			if ok != tt.ok || row != tt.row || col != tt.col {
+				t.Errorf("SeatByLabel(%q) = %d, %d, %v, want %d, %d, %v", tt.label, row, col, ok, tt.row, tt.col, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDuplicateLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *Screening
+		want string
+	}{
+		{"generated", &Screening{Rows: 30, Cols: 12}, ""},
+		{"row names ending in digits", &Screening{Rows: 2, Cols: 11, Labeling: &SeatLabeling{RowNames: []string{"1", "11"}}}, "111"},
+		{"custom label collides", gapScreening(nil, LayoutCell{Row: 0, Col: 4, Label: "a1"}), "a1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.DuplicateLabel(); got != tt.want {
+				t.Errorf("DuplicateLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
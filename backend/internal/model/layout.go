@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Layout cell kinds. SEAT, WHEELCHAIR and COMPANION can be booked; the rest only shape the hall.
+const (
+	CellSeat       = "SEAT"
+	CellWheelchair = "WHEELCHAIR" // space for a wheelchair, no fixed seat
+	CellCompanion  = "COMPANION"  // seat next to a wheelchair space for the person accompanying
+	CellAisle      = "AISLE"
+	CellStairs     = "STAIRS"
+	CellGap        = "GAP"
+)
+
+// IsSeatKind reports whether a cell of this kind can be booked.
+func IsSeatKind(kind string) bool {
+	return kind == CellSeat || kind == CellWheelchair || kind == CellCompanion
+}
+
+// IsAccessibleKind reports whether a cell is reserved for wheelchair users and their companions.
+func IsAccessibleKind(kind string) bool {
+	return kind == CellWheelchair || kind == CellCompanion
+}
+
+// Layout is the floor plan of a hall: a Rows x Cols grid where every cell is a regular seat unless listed in
+// Cells. Layouts are immutable once created so booked seats never move; create a new one to change a hall.
+type Layout struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Rows      int                `bson:"rows" json:"rows"`
+	Cols      int                `bson:"cols" json:"cols"`
+	Cells     []LayoutCell       `bson:"cells,omitempty" json:"cells,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// LayoutCell overrides the kind and/or label of one cell of a Layout.
+type LayoutCell struct {
+	Row   int    `bson:"row" json:"row"`
+	Col   int    `bson:"col" json:"col"`
+	Kind  string `bson:"kind,omitempty" json:"kind,omitempty"` // empty means SEAT
+	Label string `bson:"label,omitempty" json:"label,omitempty"`
+}
+
+// Cell returns the cell at row, col with its kind resolved.
+func (l *Layout) Cell(row, col int) LayoutCell {
+	for _, c := range l.Cells {
+		if c.Row == row && c.Col == col {
+			if c.Kind == "" {
+				c.Kind = CellSeat
+			}
+			return c
+		}
+	}
+	return LayoutCell{Row: row, Col: col, Kind: CellSeat}
+}
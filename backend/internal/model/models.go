@@ -12,6 +12,7 @@ const (
 	SeatAvailable SeatStatus = "AVAILABLE"
 	SeatLocked    SeatStatus = "LOCKED"
 	SeatBooked    SeatStatus = "BOOKED"
+	SeatNone      SeatStatus = "NONE" // layout cell that is not a seat (aisle, stairs, gap)
 )
 
 type UserRole string
@@ -22,33 +23,255 @@ const (
 )
 
 type Screening struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	MovieID   string             `bson:"movie_id" json:"movie_id"`
-	MovieName string             `bson:"movie_name" json:"movie_name"`
-	ScreenAt  time.Time          `bson:"screen_at" json:"screen_at"`
-	Rows      int                `bson:"rows" json:"rows"`
-	Cols      int                `bson:"cols" json:"cols"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MovieID        string             `bson:"movie_id" json:"movie_id"`
+	MovieName      string             `bson:"movie_name" json:"movie_name"`
+	ScreenAt       time.Time          `bson:"screen_at" json:"screen_at"`
+	Rows           int                `bson:"rows" json:"rows"`
+	Cols           int                `bson:"cols" json:"cols"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	SeatingRule    *SeatingRule       `bson:"seating_rule,omitempty" json:"seating_rule,omitempty"`
+	OnSaleAt       *time.Time         `bson:"on_sale_at,omitempty" json:"on_sale_at,omitempty"`   // nil: on sale from creation
+	OffSaleAt      *time.Time         `bson:"off_sale_at,omitempty" json:"off_sale_at,omitempty"` // nil: until the sales cutoff before ScreenAt
+	Price          float64            `bson:"price" json:"price"`                                 // price of STANDARD seats (not in any category)
+	Categories     []SeatCategory     `bson:"categories,omitempty" json:"categories,omitempty"`
+	SalesPaused    bool               `bson:"sales_paused,omitempty" json:"sales_paused"`
+	WaitingRoom    bool               `bson:"waiting_room,omitempty" json:"waiting_room"` // seat map and holds require an admission token
+	SaleState      string             `bson:"-" json:"sale_state,omitempty"`              // computed per request, see SaleStateAt
+	LayoutID       string             `bson:"layout_id,omitempty" json:"layout_id,omitempty"`
+	Layout         *Layout            `bson:"-" json:"layout,omitempty"` // loaded by the repository from LayoutID
+	CinemaID       string             `bson:"cinema_id,omitempty" json:"cinema_id,omitempty"`
+	HallID         string             `bson:"hall_id,omitempty" json:"hall_id,omitempty"`
+	RuntimeMinutes int                `bson:"runtime_minutes,omitempty" json:"runtime_minutes,omitempty"`
+	OccupiedUntil  *time.Time         `bson:"occupied_until,omitempty" json:"occupied_until,omitempty"` // ScreenAt + runtime + cleaning buffer, for screenings in a hall
+	Labeling       *SeatLabeling      `bson:"labeling,omitempty" json:"labeling,omitempty"`             // copied from the hall; nil: rows A, B, ... numbered left to right
+	Revision       int64              `bson:"revision,omitempty" json:"revision"`                       // bumped by every update of the document
+}
+
+const (
+	SaleScheduled = "SCHEDULED" // before OnSaleAt
+	SaleOnSale    = "ON_SALE"
+	SalePaused    = "PAUSED"
+	SaleClosed    = "CLOSED" // after OffSaleAt or the cutoff before ScreenAt
+)
+
+// SaleStateAt returns whether seats can be sold at now; sales always close cutoff before ScreenAt.
+func (s *Screening) SaleStateAt(now time.Time, cutoff time.Duration) string {
+	closeAt := s.ScreenAt.Add(-cutoff)
+	if s.OffSaleAt != nil && s.OffSaleAt.Before(closeAt) {
+		closeAt = *s.OffSaleAt
+	}
+	switch {
+	case !now.Before(closeAt):
+		return SaleClosed
+	case s.OnSaleAt != nil && now.Before(*s.OnSaleAt):
+		return SaleScheduled
+	case s.SalesPaused:
+		return SalePaused
+	default:
+		return SaleOnSale
+	}
+}
+
+const (
+	OrphanRuleOff    = "OFF"
+	OrphanRuleWarn   = "WARN"   // lock succeeds, response carries a warning
+	OrphanRuleReject = "REJECT" // lock is refused
+)
+
+// SeatingRule is an optional per-screening rule against leaving single empty seats stranded in a row.
+type SeatingRule struct {
+	OrphanSeat string `bson:"orphan_seat" json:"orphan_seat"`                     // OFF, WARN, REJECT
+	AisleAfter []int  `bson:"aisle_after,omitempty" json:"aisle_after,omitempty"` // aisle between col c and c+1; seats next to it are exempt like row ends
+}
+
+const (
+	CategoryStandard = "STANDARD"
+	CategoryPremium  = "PREMIUM"
+	CategoryVIP      = "VIP"
+	CategorySofa     = "SOFA"
+)
+
+// SeatCategory is a price tier of a screening, assigned to whole Rows and/or individual Seats.
+// A seat listed in Seats wins over its row; seats in no category are STANDARD at Screening.Price.
+type SeatCategory struct {
+	Code  string    `bson:"code" json:"code"` // STANDARD, PREMIUM, VIP, SOFA or any custom code
+	Name  string    `bson:"name,omitempty" json:"name,omitempty"`
+	Price float64   `bson:"price" json:"price"`
+	Rows  []int     `bson:"rows,omitempty" json:"rows,omitempty"`
+	Seats []SeatRef `bson:"seats,omitempty" json:"seats,omitempty"`
+}
+
+type SeatRef struct {
+	Row int `bson:"row" json:"row"`
+	Col int `bson:"col" json:"col"`
+}
+
+// CategoryAt returns the category code and price of a seat.
+func (s *Screening) CategoryAt(row, col int) (code string, price float64) {
+	for _, c := range s.Categories {
+		for _, ref := range c.Seats {
+			if ref.Row == row && ref.Col == col {
+				return c.Code, c.Price
+			}
+		}
+	}
+	for _, c := range s.Categories {
+		for _, r := range c.Rows {
+			if r == row {
+				return c.Code, c.Price
+			}
+		}
+	}
+	return CategoryStandard, s.Price
 }
 
 type Seat struct {
-	Row    int        `bson:"row" json:"row"`
-	Col    int        `bson:"col" json:"col"`
-	Status SeatStatus `bson:"status" json:"status"`
-	LockID string     `bson:"lock_id,omitempty" json:"lock_id,omitempty"`
-	UserID string     `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Row      int        `bson:"row" json:"row"`
+	Col      int        `bson:"col" json:"col"`
+	Status   SeatStatus `bson:"status" json:"status"`
+	LockID   string     `bson:"lock_id,omitempty" json:"lock_id,omitempty"`
+	UserID   string     `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Kind     string     `bson:"kind,omitempty" json:"kind,omitempty"` // SEAT, WHEELCHAIR, COMPANION, or a non-seat cell kind
+	Label    string     `bson:"label,omitempty" json:"label,omitempty"`
+	Category string     `bson:"category,omitempty" json:"category,omitempty"`
+	Price    float64    `bson:"price" json:"price"`
+}
+
+// SeatAt returns the cell at row, col as an AVAILABLE seat with kind, label, category and price filled in,
+// or with status NONE if the layout has no seat there. Building a whole row is cheaper with RowSeats.
+func (s *Screening) SeatAt(row, col int) Seat {
+	if row < 0 || row >= s.Rows || col < 0 || col >= s.Cols {
+		return s.seatAt(row, col)
+	}
+	return s.RowSeats(row)[col]
+}
+
+// seatAt is SeatAt without the generated label, which depends on the rest of the row.
+func (s *Screening) seatAt(row, col int) Seat {
+	cell := s.CellAt(row, col)
+	if !IsSeatKind(cell.Kind) {
+		return Seat{Row: row, Col: col, Status: SeatNone, Kind: cell.Kind}
+	}
+	code, price := s.CategoryAt(row, col)
+	return Seat{Row: row, Col: col, Status: SeatAvailable, Kind: cell.Kind, Label: cell.Label, Category: code, Price: price}
+}
+
+// CellAt returns the layout cell at row, col; screenings without a layout are all regular seats.
+func (s *Screening) CellAt(row, col int) LayoutCell {
+	if s.Layout != nil {
+		return s.Layout.Cell(row, col)
+	}
+	return LayoutCell{Row: row, Col: col, Kind: CellSeat}
+}
+
+// IsSeat reports whether row, col is inside the hall and holds something that can be booked.
+func (s *Screening) IsSeat(row, col int) bool {
+	if row < 0 || row >= s.Rows || col < 0 || col >= s.Cols {
+		return false
+	}
+	return IsSeatKind(s.CellAt(row, col).Kind)
 }
 
 type Booking struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ScreeningID   string             `bson:"screening_id" json:"screening_id"`
+	UserID        string             `bson:"user_id" json:"user_id"`
+	SeatRow       int                `bson:"seat_row" json:"seat_row"`
+	SeatCol       int                `bson:"seat_col" json:"seat_col"`
+	SeatLabel     string             `bson:"seat_label,omitempty" json:"seat_label,omitempty"` // e.g. "B12", fixed when the seat is taken
+	Status        string             `bson:"status" json:"status"`                             // PENDING, CONFIRMED, TIMEOUT, CANCELLED
+	LockID        string             `bson:"lock_id,omitempty" json:"lock_id,omitempty"`
+	LockExpiresAt *time.Time         `bson:"lock_expires_at,omitempty" json:"lock_expires_at,omitempty"`
+	ExtendCount   int                `bson:"extend_count,omitempty" json:"extend_count,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	ConfirmedAt   *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	CancelledAt   *time.Time         `bson:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+	RefundPercent int                `bson:"refund_percent,omitempty" json:"refund_percent,omitempty"`
+	OrderID       string             `bson:"order_id,omitempty" json:"order_id,omitempty"`
+	SeatCategory  string             `bson:"seat_category,omitempty" json:"seat_category,omitempty"`
+	Price         float64            `bson:"price" json:"price"`                                     // quoted when the seat was locked
+	PaidAmount    float64            `bson:"paid_amount,omitempty" json:"paid_amount,omitempty"`     // charged on confirm
+	RefundAmount  float64            `bson:"refund_amount,omitempty" json:"refund_amount,omitempty"` // PaidAmount * RefundPercent
+	GroupID       string             `bson:"group_id,omitempty" json:"group_id,omitempty"`
+	ClaimedAt     *time.Time         `bson:"claimed_at,omitempty" json:"claimed_at,omitempty"`         // group seat taken by a member
+	WaitlistOffer bool               `bson:"waitlist_offer,omitempty" json:"waitlist_offer,omitempty"` // hold created for a waitlisted user
+	TransferTo    string             `bson:"transfer_to,omitempty" json:"transfer_to,omitempty"`       // user offered the ticket, until accepted
+	TransferUntil *time.Time         `bson:"transfer_until,omitempty" json:"transfer_until,omitempty"` // the offer lapses after this
+	Transfers     []BookingTransfer  `bson:"transfers,omitempty" json:"transfers,omitempty"`
+}
+
+// BookingTransfer records one completed change of ownership of a booking.
+type BookingTransfer struct {
+	FromUserID string    `bson:"from_user_id" json:"from_user_id"`
+	ToUserID   string    `bson:"to_user_id" json:"to_user_id"`
+	At         time.Time `bson:"at" json:"at"`
+}
+
+const (
+	OrderPending   = "PENDING"
+	OrderPaid      = "PAID"
+	OrderCancelled = "CANCELLED"
+	OrderExpired   = "EXPIRED"
+
+	OrderItemSeat = "SEAT"
+)
+
+// Order groups the bookings (and later other items) of one checkout under a single total and status.
+type Order struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	ScreeningID string             `bson:"screening_id" json:"screening_id"`
 	UserID      string             `bson:"user_id" json:"user_id"`
-	SeatRow     int                `bson:"seat_row" json:"seat_row"`
-	SeatCol     int                `bson:"seat_col" json:"seat_col"`
-	Status      string             `bson:"status" json:"status"` // PENDING, CONFIRMED, TIMEOUT, CANCELLED
-	LockID      string             `bson:"lock_id,omitempty" json:"lock_id,omitempty"`
+	ScreeningID string             `bson:"screening_id" json:"screening_id"`
+	Items       []OrderItem        `bson:"items" json:"items"`
+	Total       float64            `bson:"total" json:"total"`
+	Status      string             `bson:"status" json:"status"` // PENDING, PAID, CANCELLED, EXPIRED
+	ExpiresAt   time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	PaidAt      *time.Time         `bson:"paid_at,omitempty" json:"paid_at,omitempty"`
+}
+
+// OrderItem is one line of an order; seat lines point at their Booking.
+type OrderItem struct {
+	Kind      string  `bson:"kind" json:"kind"`
+	BookingID string  `bson:"booking_id,omitempty" json:"booking_id,omitempty"`
+	SeatRow   int     `bson:"seat_row" json:"seat_row"`
+	SeatCol   int     `bson:"seat_col" json:"seat_col"`
+	SeatLabel string  `bson:"seat_label,omitempty" json:"seat_label,omitempty"`
+	Price     float64 `bson:"price" json:"price"`
+}
+
+// OrderStatusFor derives an order's status from its bookings' statuses.
+func OrderStatusFor(bookingStatuses []string) string {
+	confirmed, cancelled := 0, 0
+	for _, st := range bookingStatuses {
+		switch st {
+		case "PENDING":
+			return OrderPending
+		case "CONFIRMED":
+			confirmed++
+		case "CANCELLED":
+			cancelled++
+		}
+	}
+	switch {
+	case confirmed > 0:
+		return OrderPaid
+	case cancelled == len(bookingStatuses):
+		return OrderCancelled
+	default:
+		return OrderExpired
+	}
+}
+
+// GroupHold is a block of seats held by an organizer and shared by link; each member claims and pays for one seat.
+type GroupHold struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ScreeningID string             `bson:"screening_id" json:"screening_id"`
+	OrganizerID string             `bson:"organizer_id" json:"organizer_id"`
+	LockID      string             `bson:"lock_id" json:"-"`
+	ClaimedBy   []string           `bson:"claimed_by,omitempty" json:"-"` // members holding a claimed seat
+	ExpiresAt   time.Time          `bson:"expires_at" json:"expires_at"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	ConfirmedAt *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
 }
 
 type User struct {
@@ -67,9 +290,26 @@ type AuditLog struct {
 }
 
 const (
-	EventBookingSuccess  = "BOOKING_SUCCESS"
-	EventBookingTimeout  = "BOOKING_TIMEOUT"
-	EventSeatReleased    = "SEAT_RELEASED"
-	EventSystemError     = "SYSTEM_ERROR"
-	EventLockFailed      = "LOCK_FAIL"
+	EventBookingSuccess    = "BOOKING_SUCCESS"
+	EventBookingTimeout    = "BOOKING_TIMEOUT"
+	EventSeatReleased      = "SEAT_RELEASED"
+	EventSystemError       = "SYSTEM_ERROR"
+	EventLockFailed        = "LOCK_FAIL"
+	EventLockExtended      = "LOCK_EXTENDED"
+	EventBookingCancelled  = "BOOKING_CANCELLED"
+	EventBookingRefunded   = "BOOKING_REFUNDED"
+	EventSeatExchanged     = "SEAT_EXCHANGED"
+	EventTransferOffered   = "TRANSFER_OFFERED"
+	EventTicketTransferred = "TICKET_TRANSFERRED"
+	EventTransferRevoked   = "TRANSFER_REVOKED"
+	EventWaitlistOffer     = "WAITLIST_OFFER"
+	EventGroupHoldCreated  = "GROUP_HOLD_CREATED"
+	EventGroupSeatClaimed  = "GROUP_SEAT_CLAIMED"
+	EventSalesPaused       = "SALES_PAUSED"
+	EventSalesResumed      = "SALES_RESUMED"
+	EventReconcileFix      = "RECONCILE_FIX"
+	EventOrderPaid         = "ORDER_PAID"
+	EventQueuePosition     = "QUEUE_POSITION"
+	EventQueueAdmitted     = "QUEUE_ADMITTED"
+	EventScreeningUpdated  = "SCREENING_UPDATED"
 )
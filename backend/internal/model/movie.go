@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Movie is a catalog entry. Screenings reference it by MovieID and keep a copy of the title in MovieName.
+type Movie struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title          string             `bson:"title" json:"title"`
+	RuntimeMinutes int                `bson:"runtime_minutes" json:"runtime_minutes"`
+	AgeRating      string             `bson:"age_rating,omitempty" json:"age_rating,omitempty"` // e.g. G, PG-13, 18+
+	Genres         []string           `bson:"genres,omitempty" json:"genres,omitempty"`
+	Languages      []string           `bson:"languages,omitempty" json:"languages,omitempty"` // audio/subtitle languages, e.g. TH, EN
+	PosterURL      string             `bson:"poster_url,omitempty" json:"poster_url,omitempty"`
+	ReleaseDate    *time.Time         `bson:"release_date,omitempty" json:"release_date,omitempty"`
+	EndDate        *time.Time         `bson:"end_date,omitempty" json:"end_date,omitempty"` // last day in cinemas; nil: open-ended
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cinema is one site with one or more halls.
+type Cinema struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	City      string             `bson:"city,omitempty" json:"city,omitempty"`
+	Address   string             `bson:"address,omitempty" json:"address,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Hall is a screening room of a cinema. Screenings in a hall take its layout and may not overlap,
+// counting the film's runtime plus the cleaning buffer after it.
+type Hall struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CinemaID        string             `bson:"cinema_id" json:"cinema_id"`
+	Name            string             `bson:"name" json:"name"`
+	LayoutID        string             `bson:"layout_id" json:"layout_id"`
+	CleaningMinutes int                `bson:"cleaning_minutes,omitempty" json:"cleaning_minutes,omitempty"` // 0: server default
+	Labeling        *SeatLabeling      `bson:"labeling,omitempty" json:"labeling,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+}
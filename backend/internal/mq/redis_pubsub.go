@@ -25,7 +25,7 @@ func NewPublisher(client *redis.Client) *Publisher {
 	return &Publisher{client: client}
 }
 
-func (p *Publisher) PublishBookingSuccess(ctx context.Context, screeningID, userID, bookingID string, seatRow, seatCol int) error {
+func (p *Publisher) PublishBookingSuccess(ctx context.Context, screeningID, userID, bookingID string, seatRow, seatCol int, seatLabel string) error {
 	ev := Event{
 		Type: "BOOKING_SUCCESS",
 		Payload: map[string]any{
@@ -34,18 +34,36 @@ func (p *Publisher) PublishBookingSuccess(ctx context.Context, screeningID, user
 			"booking_id":   bookingID,
 			"seat_row":     seatRow,
 			"seat_col":     seatCol,
+			"seat_label":   seatLabel,
 		},
 	}
 	return p.publish(ctx, ev)
 }
 
-func (p *Publisher) PublishSeatReleased(ctx context.Context, screeningID string, seatRow, seatCol int) error {
+func (p *Publisher) PublishSeatReleased(ctx context.Context, screeningID string, seatRow, seatCol int, seatLabel string) error {
 	ev := Event{
 		Type: "SEAT_RELEASED",
 		Payload: map[string]any{
 			"screening_id": screeningID,
 			"seat_row":     seatRow,
 			"seat_col":     seatCol,
+			"seat_label":   seatLabel,
+		},
+	}
+	return p.publish(ctx, ev)
+}
+
+func (p *Publisher) PublishBookingRefunded(ctx context.Context, screeningID, userID, bookingID string, seatRow, seatCol int, seatLabel string, refundPercent int) error {
+	ev := Event{
+		Type: "BOOKING_REFUNDED",
+		Payload: map[string]any{
+			"screening_id":   screeningID,
+			"user_id":        userID,
+			"booking_id":     bookingID,
+			"seat_row":       seatRow,
+			"seat_col":       seatCol,
+			"seat_label":     seatLabel,
+			"refund_percent": refundPercent,
 		},
 	}
 	return p.publish(ctx, ev)
@@ -57,8 +75,8 @@ func (p *Publisher) publish(ctx context.Context, ev Event) error {
 }
 
 type Subscriber struct {
-	client    *redis.Client
-	onEvent   func(Event)
+	client  *redis.Client
+	onEvent func(Event)
 }
 
 func NewSubscriber(client *redis.Client, onEvent func(Event)) *Subscriber {
@@ -0,0 +1,94 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cinema-booking/internal/lock"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "seat_quota:"
+
+// Limiter caps held + booked seats per user per screening.
+// Holds are tracked in a Redis sorted set per (screening, user) scored by lock expiry, so the count is shared
+// by all replicas and expired holds drop out on their own; booked seats are counted by the caller from Mongo.
+type Limiter struct {
+	client *redis.Client
+	max    int
+}
+
+// NewLimiter returns a Limiter allowing max seats per user per screening; max <= 0 disables the quota.
+func NewLimiter(client *redis.Client, max int) *Limiter {
+	return &Limiter{client: client, max: max}
+}
+
+func (l *Limiter) Max() int { return l.max }
+
+func (l *Limiter) key(screeningID, userID string) string {
+	return fmt.Sprintf("%s%s:%s", keyPrefix, screeningID, userID)
+}
+
+func member(s lock.Seat) string { return fmt.Sprintf("%d:%d", s.Row, s.Col) }
+
+// Reserve atomically records seats as held by userID until expiresAt if held + booked + len(seats) stays within the quota.
+// Returns ok=false and the current held count when the request would exceed it.
+func (l *Limiter) Reserve(ctx context.Context, screeningID, userID string, seats []lock.Seat, booked int, expiresAt time.Time) (ok bool, held int, err error) {
+	if l.max <= 0 {
+		return true, 0, nil
+	}
+	script := redis.NewScript(`
+		redis.call("zremrangebyscore", KEYS[1], "-inf", ARGV[1])
+		local held = redis.call("zcard", KEYS[1])
+		local n = #ARGV - 4
+		if held + tonumber(ARGV[3]) + n > tonumber(ARGV[2]) then
+			return {0, held}
+		end
+		for i = 5, #ARGV do
+			redis.call("zadd", KEYS[1], ARGV[4], ARGV[i])
+		end
+		local ttl = tonumber(ARGV[4]) - tonumber(ARGV[1])
+		if redis.call("pttl", KEYS[1]) < ttl then
+			redis.call("pexpire", KEYS[1], ttl)
+		end
+		return {1, held + n}
+	`)
+	args := []interface{}{time.Now().UnixMilli(), l.max, booked, expiresAt.UnixMilli()}
+	for _, s := range seats {
+		args = append(args, member(s))
+	}
+	res, err := script.Run(ctx, l.client, []string{l.key(screeningID, userID)}, args...).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, int(res[1]), nil
+}
+
+// Extend moves the expiry of seats already held by userID to expiresAt.
+func (l *Limiter) Extend(ctx context.Context, screeningID, userID string, seats []lock.Seat, expiresAt time.Time) error {
+	if l.max <= 0 || len(seats) == 0 {
+		return nil
+	}
+	k := l.key(screeningID, userID)
+	score := float64(expiresAt.UnixMilli())
+	pipe := l.client.TxPipeline()
+	for _, s := range seats {
+		pipe.ZAddXX(ctx, k, redis.Z{Score: score, Member: member(s)})
+	}
+	pipe.PExpireAt(ctx, k, expiresAt)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Release drops seats from userID's held set (hold cancelled, or converted to a booking counted in Mongo).
+func (l *Limiter) Release(ctx context.Context, screeningID, userID string, seats []lock.Seat) error {
+	if l.max <= 0 || len(seats) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(seats))
+	for i, s := range seats {
+		members[i] = member(s)
+	}
+	return l.client.ZRem(ctx, l.key(screeningID, userID), members...).Err()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cinema-booking/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type migration struct {
+	ID  string
+	Run func(ctx context.Context, r *MongoRepo) error
+}
+
+// migrations run once each, in order; applied IDs are recorded in schema_migrations.
+var migrations = []migration{
+	{ID: "001_bookings_unique_confirmed_seat", Run: uniqueConfirmedSeat},
+	{ID: "002_screenings_hall_schedule", Run: hallScheduleIndex},
+	{ID: "003_movies_catalog", Run: moviesCatalog},
+	{ID: "004_bookings_seat_label", Run: bookingSeatLabels},
+}
+
+func (r *MongoRepo) migrationCol() *mongo.Collection { return r.db.Collection("schema_migrations") }
+
+// Migrate applies pending migrations. A failing migration is logged and stops the run; it is retried on next start.
+func (r *MongoRepo) Migrate(ctx context.Context) error {
+	for _, m := range migrations {
+		n, err := r.migrationCol().CountDocuments(ctx, bson.M{"_id": m.ID})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		if err := m.Run(ctx, r); err != nil {
+			return fmt.Errorf("migration %s: %w", m.ID, err)
+		}
+		if _, err := r.migrationCol().InsertOne(ctx, bson.M{"_id": m.ID, "applied_at": time.Now()}); err != nil {
+			return err
+		}
+		log.Printf("migrate: applied %s", m.ID)
+	}
+	return nil
+}
+
+// uniqueConfirmedSeat enforces at most one CONFIRMED booking per (screening_id, seat_row, seat_col).
+// PENDING rows are left out: a timed-out hold stays PENDING until the expiry worker runs, while the Redis lock
+// already lets the next customer take the seat.
+func uniqueConfirmedSeat(ctx context.Context, r *MongoRepo) error {
+	cur, err := r.bookingCol().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "CONFIRMED"}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"screening_id": "$screening_id", "seat_row": "$seat_row", "seat_col": "$seat_col"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var dups []bson.M
+	if err := cur.All(ctx, &dups); err != nil {
+		return err
+	}
+	if len(dups) > 0 {
+		return fmt.Errorf("%d seats have more than one CONFIRMED booking, resolve them before the index can be built: %v", len(dups), dups)
+	}
+	_, err = r.bookingCol().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "screening_id", Value: 1}, {Key: "seat_row", Value: 1}, {Key: "seat_col", Value: 1}},
+		Options: options.Index().
+			SetName("uniq_confirmed_seat").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": "CONFIRMED"}),
+	})
+	return err
+}
+
+// hallScheduleIndex backs the overlap query run when a screening is scheduled in a hall.
+func hallScheduleIndex(ctx context.Context, r *MongoRepo) error {
+	_, err := r.screeningCol().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hall_id", Value: 1}, {Key: "screen_at", Value: 1}},
+		Options: options.Index().SetName("hall_schedule").SetPartialFilterExpression(bson.M{"hall_id": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+// moviesCatalog creates a catalog movie for every free-text movie_id found on screenings and points those
+// screenings at it. The title is the most recent movie_name used with that ID and the runtime the longest one
+// recorded; screenings already referencing a catalog movie, or with no movie_id at all, are left alone.
+func moviesCatalog(ctx context.Context, r *MongoRepo) error {
+	cur, err := r.screeningCol().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"movie_id": bson.M{"$type": "string", "$ne": ""}}}},
+		{{Key: "$sort", Value: bson.M{"created_at": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$movie_id",
+			"title":   bson.M{"$last": "$movie_name"},
+			"runtime": bson.M{"$max": "$runtime_minutes"},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		MovieID string `bson:"_id"`
+		Title   string `bson:"title"`
+		Runtime int    `bson:"runtime"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if _, err := r.GetMovie(ctx, g.MovieID); err == nil {
+			continue
+		}
+		m := &model.Movie{Title: g.Title, RuntimeMinutes: g.Runtime}
+		if m.Title == "" {
+			m.Title = g.MovieID
+		}
+		if err := r.CreateMovie(ctx, m); err != nil {
+			return err
+		}
+		if _, err := r.screeningCol().UpdateMany(ctx, bson.M{"movie_id": g.MovieID}, bson.M{"$set": bson.M{"movie_id": m.ID.Hex(), "movie_name": m.Title}}); err != nil {
+			return err
+		}
+		log.Printf("migrate: movie %q -> %s (%s)", g.MovieID, m.ID.Hex(), m.Title)
+	}
+	_, err = r.screeningCol().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "movie_id", Value: 1}, {Key: "screen_at", Value: 1}},
+		Options: options.Index().SetName("movie_schedule"),
+	})
+	return err
+}
+
+// bookingSeatLabels fills seat_label on bookings made before seats had labels, using each screening's labeling.
+func bookingSeatLabels(ctx context.Context, r *MongoRepo) error {
+	missing := bson.M{"seat_label": bson.M{"$exists": false}}
+	ids, err := r.bookingCol().Distinct(ctx, "screening_id", missing)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		screeningID, _ := id.(string)
+		s, err := r.GetScreening(ctx, screeningID)
+		if err != nil {
+			log.Printf("migrate: seat labels: screening %s: %v", screeningID, err)
+			continue
+		}
+		bookings, err := r.ListBookings(ctx, bson.M{"screening_id": screeningID, "seat_label": bson.M{"$exists": false}})
+		if err != nil {
+			return err
+		}
+		for _, b := range bookings {
+			label := s.SeatLabel(b.SeatRow, b.SeatCol)
+			if _, err := r.bookingCol().UpdateByID(ctx, b.ID, bson.M{"$set": bson.M{"seat_label": label}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"cinema-booking/internal/lock"
 	"cinema-booking/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,6 +13,17 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrNotPending is returned when a booking update lost a race: the booking is no longer PENDING.
+	ErrNotPending = errors.New("booking is no longer pending")
+	// ErrSeatTaken is returned when another CONFIRMED booking already holds the seat (unique index).
+	ErrSeatTaken = errors.New("seat already booked")
+	// ErrInUse is returned when deleting something other documents still reference.
+	ErrInUse = errors.New("still in use")
+	// ErrAlreadyClaimed is returned when the user already holds a claimed seat of the group.
+	ErrAlreadyClaimed = errors.New("seat already claimed in this group")
+)
+
 type MongoRepo struct {
 	db *mongo.Database
 }
@@ -22,6 +35,12 @@ func NewMongoRepo(db *mongo.Database) *MongoRepo {
 func (r *MongoRepo) screeningCol() *mongo.Collection { return r.db.Collection("screenings") }
 func (r *MongoRepo) bookingCol() *mongo.Collection   { return r.db.Collection("bookings") }
 func (r *MongoRepo) userCol() *mongo.Collection     { return r.db.Collection("users") }
+func (r *MongoRepo) groupHoldCol() *mongo.Collection { return r.db.Collection("group_holds") }
+func (r *MongoRepo) orderCol() *mongo.Collection     { return r.db.Collection("orders") }
+func (r *MongoRepo) layoutCol() *mongo.Collection    { return r.db.Collection("layouts") }
+func (r *MongoRepo) movieCol() *mongo.Collection     { return r.db.Collection("movies") }
+func (r *MongoRepo) cinemaCol() *mongo.Collection    { return r.db.Collection("cinemas") }
+func (r *MongoRepo) hallCol() *mongo.Collection      { return r.db.Collection("halls") }
 func (r *MongoRepo) auditCol() *mongo.Collection    { return r.db.Collection("audit_logs") }
 
 func (r *MongoRepo) AuditCol() *mongo.Collection { return r.auditCol() }
@@ -45,11 +64,36 @@ func (r *MongoRepo) GetScreening(ctx context.Context, id string) (*model.Screeni
 		return nil, err
 	}
 	s.ID = oid
+	if err := r.attachLayouts(ctx, []*model.Screening{&s}); err != nil {
+		return nil, err
+	}
 	return &s, nil
 }
 
+// revisionInc bumps Screening.Revision; every update of a screening document applies it.
+var revisionInc = bson.M{"revision": 1}
+
 func (r *MongoRepo) ListScreenings(ctx context.Context) ([]*model.Screening, error) {
-	cur, err := r.screeningCol().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"screen_at": 1}))
+	return r.FindScreenings(ctx, bson.M{})
+}
+
+// FindScreeningsByID returns the screenings with the given IDs, earliest first. Invalid IDs are skipped.
+func (r *MongoRepo) FindScreeningsByID(ctx context.Context, ids []string) ([]*model.Screening, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return nil, nil
+	}
+	return r.FindScreenings(ctx, bson.M{"_id": bson.M{"$in": oids}})
+}
+
+// FindScreenings returns the screenings matching filter, earliest first.
+func (r *MongoRepo) FindScreenings(ctx context.Context, filter bson.M) ([]*model.Screening, error) {
+	cur, err := r.screeningCol().Find(ctx, filter, options.Find().SetSort(bson.M{"screen_at": 1}))
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +102,432 @@ func (r *MongoRepo) ListScreenings(ctx context.Context) ([]*model.Screening, err
 	if err := cur.All(ctx, &out); err != nil {
 		return nil, err
 	}
+	if err := r.attachLayouts(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// attachLayouts loads the Layout of every screening that references one, one query for all of them.
+func (r *MongoRepo) attachLayouts(ctx context.Context, list []*model.Screening) error {
+	var ids []primitive.ObjectID
+	for _, s := range list {
+		if s.LayoutID == "" {
+			continue
+		}
+		if oid, err := primitive.ObjectIDFromHex(s.LayoutID); err == nil {
+			ids = append(ids, oid)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	cur, err := r.layoutCol().Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+	var layouts []*model.Layout
+	if err := cur.All(ctx, &layouts); err != nil {
+		return err
+	}
+	byID := make(map[string]*model.Layout, len(layouts))
+	for _, l := range layouts {
+		byID[l.ID.Hex()] = l
+	}
+	for _, s := range list {
+		if s.LayoutID != "" {
+			s.Layout = byID[s.LayoutID]
+		}
+	}
+	return nil
+}
+
+func (r *MongoRepo) CreateLayout(ctx context.Context, l *model.Layout) error {
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	if l.ID.IsZero() {
+		l.ID = primitive.NewObjectID()
+	}
+	_, err := r.layoutCol().InsertOne(ctx, l)
+	return err
+}
+
+func (r *MongoRepo) GetLayout(ctx context.Context, id string) (*model.Layout, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var l model.Layout
+	if err := r.layoutCol().FindOne(ctx, bson.M{"_id": oid}).Decode(&l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *MongoRepo) ListLayouts(ctx context.Context) ([]*model.Layout, error) {
+	cur, err := r.layoutCol().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}).SetProjection(bson.M{"cells": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	out := []*model.Layout{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteLayout removes a layout no screening uses. Returns ErrInUse otherwise.
+func (r *MongoRepo) DeleteLayout(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	n, err := r.screeningCol().CountDocuments(ctx, bson.M{"layout_id": id})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrInUse
+	}
+	res, err := r.layoutCol().DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateScreening sets fields of a screening. Returns mongo.ErrNoDocuments if it does not exist.
+func (r *MongoRepo) UpdateScreening(ctx context.Context, id string, set bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.screeningCol().UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": set, "$inc": revisionInc})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// FindOverlappingScreening returns a screening in hallID whose [screen_at, occupied_until) intersects [start, end),
+// ignoring excludeID, or nil if the slot is free.
+func (r *MongoRepo) FindOverlappingScreening(ctx context.Context, hallID string, start, end time.Time, excludeID string) (*model.Screening, error) {
+	filter := bson.M{
+		"hall_id":        hallID,
+		"screen_at":      bson.M{"$lt": end},
+		"occupied_until": bson.M{"$gt": start},
+	}
+	if oid, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+		filter["_id"] = bson.M{"$ne": oid}
+	}
+	var s model.Screening
+	err := r.screeningCol().FindOne(ctx, filter).Decode(&s)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *MongoRepo) CreateMovie(ctx context.Context, m *model.Movie) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if m.ID.IsZero() {
+		m.ID = primitive.NewObjectID()
+	}
+	_, err := r.movieCol().InsertOne(ctx, m)
+	return err
+}
+
+func (r *MongoRepo) GetMovie(ctx context.Context, id string) (*model.Movie, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var m model.Movie
+	if err := r.movieCol().FindOne(ctx, bson.M{"_id": oid}).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetMovies returns the movies with the given IDs in one query, keyed by ID. Unknown or invalid IDs are left out.
+func (r *MongoRepo) GetMovies(ctx context.Context, ids []string) (map[string]*model.Movie, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	out := make(map[string]*model.Movie, len(oids))
+	if len(oids) == 0 {
+		return out, nil
+	}
+	list, err := r.ListMovies(ctx, bson.M{"_id": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range list {
+		out[m.ID.Hex()] = m
+	}
+	return out, nil
+}
+
+// ListMovies returns the movies matching filter, by title.
+func (r *MongoRepo) ListMovies(ctx context.Context, filter bson.M) ([]*model.Movie, error) {
+	cur, err := r.movieCol().Find(ctx, filter, options.Find().SetSort(bson.M{"title": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	out := []*model.Movie{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateMovie sets fields of a movie and copies a changed title to its screenings' movie_name.
+// Returns mongo.ErrNoDocuments if it does not exist.
+func (r *MongoRepo) UpdateMovie(ctx context.Context, id string, set bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.movieCol().UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	if title, ok := set["title"]; ok {
+		_, err = r.screeningCol().UpdateMany(ctx, bson.M{"movie_id": id}, bson.M{"$set": bson.M{"movie_name": title}, "$inc": revisionInc})
+	}
+	return err
+}
+
+// DeleteMovie removes a movie no screening references. Returns ErrInUse otherwise.
+func (r *MongoRepo) DeleteMovie(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	n, err := r.screeningCol().CountDocuments(ctx, bson.M{"movie_id": id})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrInUse
+	}
+	res, err := r.movieCol().DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *MongoRepo) CreateCinema(ctx context.Context, cinema *model.Cinema) error {
+	if cinema.CreatedAt.IsZero() {
+		cinema.CreatedAt = time.Now()
+	}
+	if cinema.ID.IsZero() {
+		cinema.ID = primitive.NewObjectID()
+	}
+	_, err := r.cinemaCol().InsertOne(ctx, cinema)
+	return err
+}
+
+func (r *MongoRepo) GetCinema(ctx context.Context, id string) (*model.Cinema, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var cinema model.Cinema
+	if err := r.cinemaCol().FindOne(ctx, bson.M{"_id": oid}).Decode(&cinema); err != nil {
+		return nil, err
+	}
+	return &cinema, nil
+}
+
+func (r *MongoRepo) ListCinemas(ctx context.Context) ([]*model.Cinema, error) {
+	cur, err := r.cinemaCol().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	out := []*model.Cinema{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateCinema sets fields of a cinema. Returns mongo.ErrNoDocuments if it does not exist.
+func (r *MongoRepo) UpdateCinema(ctx context.Context, id string, set bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.cinemaCol().UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteCinema removes a cinema without halls. Returns ErrInUse otherwise.
+func (r *MongoRepo) DeleteCinema(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	n, err := r.hallCol().CountDocuments(ctx, bson.M{"cinema_id": id})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrInUse
+	}
+	res, err := r.cinemaCol().DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *MongoRepo) CreateHall(ctx context.Context, hall *model.Hall) error {
+	if hall.CreatedAt.IsZero() {
+		hall.CreatedAt = time.Now()
+	}
+	if hall.ID.IsZero() {
+		hall.ID = primitive.NewObjectID()
+	}
+	_, err := r.hallCol().InsertOne(ctx, hall)
+	return err
+}
+
+func (r *MongoRepo) GetHall(ctx context.Context, id string) (*model.Hall, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var hall model.Hall
+	if err := r.hallCol().FindOne(ctx, bson.M{"_id": oid}).Decode(&hall); err != nil {
+		return nil, err
+	}
+	return &hall, nil
+}
+
+// ListHalls returns the halls of a cinema, or all halls if cinemaID is empty.
+func (r *MongoRepo) ListHalls(ctx context.Context, cinemaID string) ([]*model.Hall, error) {
+	filter := bson.M{}
+	if cinemaID != "" {
+		filter["cinema_id"] = cinemaID
+	}
+	cur, err := r.hallCol().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "cinema_id", Value: 1}, {Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	out := []*model.Hall{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
 	return out, nil
 }
 
+// UpdateHall sets fields of a hall. Returns mongo.ErrNoDocuments if it does not exist.
+func (r *MongoRepo) UpdateHall(ctx context.Context, id string, set bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.hallCol().UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteHall removes a hall no screening is scheduled in. Returns ErrInUse otherwise.
+func (r *MongoRepo) DeleteHall(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	n, err := r.screeningCol().CountDocuments(ctx, bson.M{"hall_id": id})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrInUse
+	}
+	res, err := r.hallCol().DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *MongoRepo) SetSalesPaused(ctx context.Context, id string, paused bool) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.screeningCol().UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"sales_paused": paused}, "$inc": revisionInc})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetWaitingRoom turns the admission queue of a screening on or off.
+func (r *MongoRepo) SetWaitingRoom(ctx context.Context, id string, enabled bool) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.screeningCol().UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"waiting_room": enabled}, "$inc": revisionInc})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 func (r *MongoRepo) CreateBooking(ctx context.Context, b *model.Booking) error {
 	if b.CreatedAt.IsZero() {
 		b.CreatedAt = time.Now()
@@ -73,6 +540,26 @@ func (r *MongoRepo) CreateBooking(ctx context.Context, b *model.Booking) error {
 	return nil
 }
 
+// CreateBookings inserts all bookings in one InsertMany and fills in their IDs.
+func (r *MongoRepo) CreateBookings(ctx context.Context, list []*model.Booking) error {
+	docs := make([]interface{}, len(list))
+	now := time.Now()
+	for i, b := range list {
+		if b.CreatedAt.IsZero() {
+			b.CreatedAt = now
+		}
+		docs[i] = b
+	}
+	res, err := r.bookingCol().InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+	for i, id := range res.InsertedIDs {
+		list[i].ID = id.(primitive.ObjectID)
+	}
+	return nil
+}
+
 func (r *MongoRepo) GetBookingByID(ctx context.Context, id string) (*model.Booking, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -95,15 +582,26 @@ func (r *MongoRepo) GetBookingByLock(ctx context.Context, screeningID, lockID st
 	return &b, nil
 }
 
-func (r *MongoRepo) ConfirmBooking(ctx context.Context, bookingID string) error {
+// ConfirmBooking moves a PENDING booking to CONFIRMED and records the amount charged.
+// Returns ErrNotPending or ErrSeatTaken if it lost a race.
+func (r *MongoRepo) ConfirmBooking(ctx context.Context, bookingID string, paidAmount float64) error {
 	oid, err := primitive.ObjectIDFromHex(bookingID)
 	if err != nil {
 		return err
 	}
 	now := time.Now()
-	_, err = r.bookingCol().UpdateOne(ctx, bson.M{"_id": oid},
-		bson.M{"$set": bson.M{"status": "CONFIRMED", "confirmed_at": now}})
-	return err
+	res, err := r.bookingCol().UpdateOne(ctx, bson.M{"_id": oid, "status": "PENDING"},
+		bson.M{"$set": bson.M{"status": "CONFIRMED", "confirmed_at": now, "paid_amount": paidAmount}})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSeatTaken
+	}
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount != 1 {
+		return ErrNotPending
+	}
+	return nil
 }
 
 func (r *MongoRepo) SetBookingStatus(ctx context.Context, bookingID, status string) error {
@@ -130,6 +628,122 @@ func (r *MongoRepo) SetBookingStatusIfPending(ctx context.Context, bookingID, ne
 	return res.ModifiedCount == 1, nil
 }
 
+// ExtendBookingLock moves lock_expires_at forward for the PENDING bookings holding lockID and bumps their extend_count,
+// unless gateID (one of them) has already been extended max times. Concurrent extends of the same hold all race on
+// the gate document, so only max of them can win. Returns false if the limit was reached or the hold is gone.
+func (r *MongoRepo) ExtendBookingLock(ctx context.Context, screeningID, lockID, gateID string, max int, expiresAt time.Time) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(gateID)
+	if err != nil {
+		return false, err
+	}
+	var gate model.Booking
+	err = r.bookingCol().FindOneAndUpdate(ctx,
+		bson.M{"_id": oid, "lock_id": lockID, "status": "PENDING", "extend_count": bson.M{"$not": bson.M{"$gte": max}}},
+		bson.M{"$set": bson.M{"lock_expires_at": expiresAt}, "$inc": bson.M{"extend_count": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&gate)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, err = r.bookingCol().UpdateMany(ctx,
+		bson.M{"screening_id": screeningID, "lock_id": lockID, "status": "PENDING", "_id": bson.M{"$ne": oid}},
+		bson.M{"$set": bson.M{"lock_expires_at": expiresAt, "extend_count": gate.ExtendCount}})
+	return err == nil, err
+}
+
+// CancelConfirmedBooking sets a CONFIRMED booking to CANCELLED with its refund percent and amount. Returns true if updated.
+func (r *MongoRepo) CancelConfirmedBooking(ctx context.Context, bookingID string, refundPercent int, refundAmount float64) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.bookingCol().UpdateOne(ctx,
+		bson.M{"_id": oid, "status": "CONFIRMED"},
+		bson.M{
+			"$set":   bson.M{"status": "CANCELLED", "cancelled_at": time.Now(), "refund_percent": refundPercent, "refund_amount": refundAmount},
+			"$unset": transferOfferUnset,
+		})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// MoveConfirmedBooking moves a CONFIRMED booking from (fromRow, fromCol) to (toRow, toCol) labeled toLabel.
+// Returns true if updated.
+func (r *MongoRepo) MoveConfirmedBooking(ctx context.Context, bookingID string, fromRow, fromCol, toRow, toCol int, toLabel string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.bookingCol().UpdateOne(ctx,
+		bson.M{"_id": oid, "status": "CONFIRMED", "seat_row": fromRow, "seat_col": fromCol},
+		bson.M{"$set": bson.M{"seat_row": toRow, "seat_col": toCol, "seat_label": toLabel}, "$unset": transferOfferUnset})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, ErrSeatTaken
+	}
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// transferOfferUnset clears a pending transfer offer; the ticket it was made for is no longer the same.
+var transferOfferUnset = bson.M{"transfer_to": "", "transfer_until": ""}
+
+// OfferTransfer marks a CONFIRMED booking owned by ownerID as offered to toUserID until the given time.
+// Returns true if updated.
+func (r *MongoRepo) OfferTransfer(ctx context.Context, bookingID, ownerID, toUserID string, until time.Time) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.bookingCol().UpdateOne(ctx,
+		bson.M{"_id": oid, "status": "CONFIRMED", "user_id": ownerID},
+		bson.M{"$set": bson.M{"transfer_to": toUserID, "transfer_until": until}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+// RevokeTransfer withdraws the pending transfer offer of a booking owned by ownerID. Returns true if there was one.
+func (r *MongoRepo) RevokeTransfer(ctx context.Context, bookingID, ownerID string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.bookingCol().UpdateOne(ctx,
+		bson.M{"_id": oid, "user_id": ownerID, "transfer_to": bson.M{"$exists": true}},
+		bson.M{"$unset": transferOfferUnset})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// AcceptTransfer moves ownership of an offered CONFIRMED booking to toUserID and appends to its transfer history.
+// Returns true if updated; an expired offer is not.
+func (r *MongoRepo) AcceptTransfer(ctx context.Context, bookingID, fromUserID, toUserID string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.bookingCol().UpdateOne(ctx,
+		bson.M{"_id": oid, "status": "CONFIRMED", "user_id": fromUserID, "transfer_to": toUserID, "transfer_until": bson.M{"$gt": time.Now()}},
+		bson.M{
+			"$set":   bson.M{"user_id": toUserID},
+			"$unset": transferOfferUnset,
+			"$push":  bson.M{"transfers": model.BookingTransfer{FromUserID: fromUserID, ToUserID: toUserID, At: time.Now()}},
+		})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
 func (r *MongoRepo) ListBookings(ctx context.Context, filter bson.M) ([]*model.Booking, error) {
 	cur, err := r.bookingCol().Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
 	if err != nil {
@@ -143,6 +757,182 @@ func (r *MongoRepo) ListBookings(ctx context.Context, filter bson.M) ([]*model.B
 	return out, nil
 }
 
+// ListBookingsPage returns one page of bookings matching filter plus the total match count. Bookings are
+// ordered by the position of their screening in screeningOrder, newest first within a screening.
+func (r *MongoRepo) ListBookingsPage(ctx context.Context, filter bson.M, screeningOrder []string, skip, limit int) ([]*model.Booking, int, error) {
+	total, err := r.bookingCol().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if screeningOrder == nil {
+		screeningOrder = []string{}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{"_order": bson.M{"$indexOfArray": bson.A{screeningOrder, "$screening_id"}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_order", Value: 1}, {Key: "created_at", Value: -1}}}},
+		{{Key: "$skip", Value: int64(skip)}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+	cur, err := r.bookingCol().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cur.Close(ctx)
+	out := []*model.Booking{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, 0, err
+	}
+	return out, int(total), nil
+}
+
+// BookingScreeningIDs returns the distinct screening IDs of the bookings matching filter.
+func (r *MongoRepo) BookingScreeningIDs(ctx context.Context, filter bson.M) ([]string, error) {
+	raw, err := r.bookingCol().Distinct(ctx, "screening_id", filter)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if id, ok := v.(string); ok {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func (r *MongoRepo) CountBookings(ctx context.Context, filter bson.M) (int, error) {
+	n, err := r.bookingCol().CountDocuments(ctx, filter)
+	return int(n), err
+}
+
+// CountSoldSeats counts the CONFIRMED bookings among seats. Confirmed seats hold no Redis lock, so anything
+// taking a seat must check here as well as acquire its lock.
+func (r *MongoRepo) CountSoldSeats(ctx context.Context, screeningID string, seats []lock.Seat) (int, error) {
+	or := make(bson.A, len(seats))
+	for i, st := range seats {
+		or[i] = bson.M{"seat_row": st.Row, "seat_col": st.Col}
+	}
+	return r.CountBookings(ctx, bson.M{"screening_id": screeningID, "status": "CONFIRMED", "$or": or})
+}
+
+func (r *MongoRepo) CreateGroupHold(ctx context.Context, g *model.GroupHold) error {
+	if g.CreatedAt.IsZero() {
+		g.CreatedAt = time.Now()
+	}
+	_, err := r.groupHoldCol().InsertOne(ctx, g)
+	return err
+}
+
+func (r *MongoRepo) GetGroupHold(ctx context.Context, id string) (*model.GroupHold, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var g model.GroupHold
+	if err := r.groupHoldCol().FindOne(ctx, bson.M{"_id": oid}).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// ClaimGroupSeat hands an unclaimed PENDING seat of a group hold to userID. Returns true if updated.
+// The user is first added to the group's claimed_by list, so concurrent claims by the same user
+// cannot both win; ErrAlreadyClaimed is returned when the user is already on it.
+func (r *MongoRepo) ClaimGroupSeat(ctx context.Context, bookingID, groupID, userID string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return false, err
+	}
+	gid, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.groupHoldCol().UpdateOne(ctx,
+		bson.M{"_id": gid, "claimed_by": bson.M{"$ne": userID}},
+		bson.M{"$push": bson.M{"claimed_by": userID}})
+	if err != nil {
+		return false, err
+	}
+	if res.MatchedCount == 0 {
+		return false, ErrAlreadyClaimed
+	}
+	res, err = r.bookingCol().UpdateOne(ctx,
+		bson.M{"_id": oid, "group_id": groupID, "status": "PENDING", "claimed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"user_id": userID, "claimed_at": time.Now()}})
+	if err != nil || res.ModifiedCount == 0 {
+		_ = r.ReleaseGroupClaim(ctx, groupID, userID)
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseGroupClaim takes userID off the group's claimed_by list so they can claim another seat.
+func (r *MongoRepo) ReleaseGroupClaim(ctx context.Context, groupID, userID string) error {
+	gid, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return err
+	}
+	_, err = r.groupHoldCol().UpdateOne(ctx, bson.M{"_id": gid}, bson.M{"$pull": bson.M{"claimed_by": userID}})
+	return err
+}
+
+func (r *MongoRepo) CreateOrder(ctx context.Context, o *model.Order) error {
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = time.Now()
+	}
+	_, err := r.orderCol().InsertOne(ctx, o)
+	return err
+}
+
+func (r *MongoRepo) GetOrder(ctx context.Context, id string) (*model.Order, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var o model.Order
+	if err := r.orderCol().FindOne(ctx, bson.M{"_id": oid}).Decode(&o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// SetOrderExpiry moves the expiry of a PENDING order, following an extension of its seat locks.
+func (r *MongoRepo) SetOrderExpiry(ctx context.Context, orderID string, expiresAt time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return err
+	}
+	_, err = r.orderCol().UpdateOne(ctx, bson.M{"_id": oid, "status": model.OrderPending}, bson.M{"$set": bson.M{"expires_at": expiresAt}})
+	return err
+}
+
+// SyncOrderStatus recomputes an order's status from its bookings and stores it. Returns the new status.
+func (r *MongoRepo) SyncOrderStatus(ctx context.Context, orderID string) (string, error) {
+	oid, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return "", err
+	}
+	list, err := r.ListBookings(ctx, bson.M{"order_id": orderID})
+	if err != nil {
+		return "", err
+	}
+	statuses := make([]string, len(list))
+	for i, b := range list {
+		statuses[i] = b.Status
+	}
+	status := model.OrderStatusFor(statuses)
+	set := bson.M{"status": status}
+	filter := bson.M{"_id": oid}
+	if status == model.OrderPaid {
+		// paid_at is set once, on the first transition to PAID
+		filter["status"] = bson.M{"$ne": model.OrderPaid}
+		set["paid_at"] = time.Now()
+	}
+	_, err = r.orderCol().UpdateOne(ctx, filter, bson.M{"$set": set})
+	return status, err
+}
+
 func (r *MongoRepo) UpsertUser(ctx context.Context, u *model.User) error {
 	set := bson.M{"email": u.Email, "name": u.Name, "role": u.Role}
 	if u.PasswordHash != "" {
@@ -0,0 +1,110 @@
+package seating
+
+import (
+	"math"
+	"sort"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+)
+
+// Preference steers which rows rank best.
+type Preference string
+
+const (
+	PreferCenter Preference = "center"
+	PreferBack   Preference = "back"
+	PreferFront  Preference = "front"
+)
+
+// Block is a run of adjacent free seats in one row.
+type Block struct {
+	Row    int         `json:"row"`
+	Seats  []lock.Seat `json:"seats"`
+	Labels []string    `json:"labels"` // label of each seat, e.g. "F7"
+	Score  float64     `json:"score"`
+}
+
+// BestBlocks returns up to limit blocks of size adjacent AVAILABLE seats from the seat map, best first.
+// Blocks closer to the horizontal center rank higher; the row weighting follows pref. Aisles (aisleAfter and
+// aisle cells) and other non-seat cells break a block, and wheelchair and companion spaces are never picked
+// automatically.
+func BestBlocks(seats [][]model.Seat, size int, pref Preference, limit int, aisleAfter []int) []Block {
+	rows := len(seats)
+	if rows == 0 || size <= 0 {
+		return nil
+	}
+	aisle := make(map[int]bool, len(aisleAfter))
+	for _, c := range aisleAfter {
+		aisle[c] = true
+	}
+	var out []Block
+	for r, row := range seats {
+		cols := len(row)
+		run := 0
+		for c := 0; c < cols; c++ {
+			if row[c].Status != model.SeatAvailable || model.IsAccessibleKind(row[c].Kind) {
+				run = 0
+				continue
+			}
+			if aisle[c-1] {
+				run = 0
+			}
+			run++
+			if run < size {
+				continue
+			}
+			start := c - size + 1
+			b := Block{Row: r, Seats: make([]lock.Seat, size), Labels: make([]string, size)}
+			for i := range b.Seats {
+				b.Seats[i] = lock.Seat{Row: r, Col: start + i}
+				b.Labels[i] = row[start+i].Label
+			}
+			b.Score = score(r, rows, start, size, cols, pref)
+			out = append(out, b)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// AvoidOrphans drops the blocks that would leave a single seat stranded (see OrphanSeats). With keep set they
+// are moved behind the clean blocks instead, for rules that only warn. The order is otherwise kept.
+func AvoidOrphans(seats [][]model.Seat, blocks []Block, aisleAfter []int, keep bool) []Block {
+	var clean, stranding []Block
+	for _, b := range blocks {
+		if len(OrphanSeats(seats, b.Seats, aisleAfter)) > 0 {
+			stranding = append(stranding, b)
+			continue
+		}
+		clean = append(clean, b)
+	}
+	if keep {
+		return append(clean, stranding...)
+	}
+	return clean
+}
+
+// score is in [0,1]: 60% horizontal centering, 40% distance from the preferred row.
+func score(row, rows, start, size, cols int, pref Preference) float64 {
+	mid := float64(start) + float64(size-1)/2
+	colDist := 0.0
+	if cols > 1 {
+		colDist = math.Abs(mid-float64(cols-1)/2) / (float64(cols-1) / 2)
+	}
+	ideal := float64(rows-1) * 0.6 // a little behind the middle
+	switch pref {
+	case PreferBack:
+		ideal = float64(rows - 1)
+	case PreferFront:
+		ideal = 0
+	}
+	rowDist := 0.0
+	if rows > 1 {
+		rowDist = math.Abs(float64(row)-ideal) / float64(rows-1)
+	}
+	return math.Round((1-(0.6*colDist+0.4*rowDist))*1000) / 1000
+}
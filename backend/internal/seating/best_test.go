@@ -0,0 +1,122 @@
+package seating
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+)
+
+// grid builds a seat map from one string per row: '.' free, 'x' booked, 'w' free wheelchair space,
+// '_' a non-seat cell. Seats are labelled A1, A2, ... like a plain layout.
+func grid(rows ...string) [][]model.Seat {
+	out := make([][]model.Seat, len(rows))
+	for r, line := range rows {
+		out[r] = make([]model.Seat, len(line))
+		for c, ch := range line {
+			seat := model.Seat{Row: r, Col: c, Status: model.SeatAvailable, Kind: model.CellSeat, Label: fmt.Sprintf("%c%d", 'A'+r, c+1)}
+			switch ch {
+			case 'x':
+				seat.Status = model.SeatBooked
+			case 'w':
+				seat.Kind = model.CellWheelchair
+			case '_':
+				seat = model.Seat{Row: r, Col: c, Status: model.SeatNone}
+			}
+			out[r][c] = seat
+		}
+	}
+	return out
+}
+
+// blockLabels renders each block as its seat labels, e.g. "A3 A4".
+func blockLabels(blocks []Block) []string {
+	var out []string
+	for _, b := range blocks {
+		out = append(out, strings.Join(b.Labels, " "))
+	}
+	return out
+}
+
+func TestBestBlocks(t *testing.T) {
+	tests := []struct {
+		name       string
+		seats      [][]model.Seat
+		size       int
+		pref       Preference
+		limit      int
+		aisleAfter []int
+		want       []string
+	}{
+		{"centered block first", grid("......"), 2, PreferCenter, 0, nil, []string{"A3 A4", "A2 A3", "A4 A5", "A1 A2", "A5 A6"}},
+		{"limit", grid("......"), 2, PreferCenter, 1, nil, []string{"A3 A4"}},
+		{"front row", grid("..", "..", ".."), 2, PreferFront, 1, nil, []string{"A1 A2"}},
+		{"back row", grid("..", "..", ".."), 2, PreferBack, 1, nil, []string{"C1 C2"}},
+		{"center row is a little behind the middle", grid("..", "..", ".."), 2, PreferCenter, 1, nil, []string{"B1 B2"}},
+		{"taken seat breaks a block", grid("..x.."), 3, PreferCenter, 0, nil, nil},
+		{"non-seat cell breaks a block", grid(".._.."), 2, PreferCenter, 0, nil, []string{"A1 A2", "A4 A5"}},
+		{"aisle breaks a block", grid("...."), 2, PreferCenter, 0, []int{1}, []string{"A1 A2", "A3 A4"}},
+		{"no block across the aisle", grid("...."), 3, PreferCenter, 0, []int{1}, nil},
+		{"wheelchair space is not picked", grid(".w.."), 2, PreferCenter, 0, nil, []string{"A3 A4"}},
+		{"size larger than the row", grid(".."), 3, PreferCenter, 0, nil, nil},
+		{"no size", grid(".."), 0, PreferCenter, 0, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := blockLabels(BestBlocks(tt.seats, tt.size, tt.pref, tt.limit, tt.aisleAfter))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BestBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name                         string
+		row, rows, start, size, cols int
+		pref                         Preference
+		want                         float64
+	}{
+		{"single seat", 0, 1, 0, 1, 1, PreferCenter, 1},
+		{"centered in the back row", 4, 5, 4, 2, 10, PreferBack, 1},
+		{"centered in the front row", 0, 5, 4, 2, 10, PreferFront, 1},
+		{"centered but furthest from the preferred row", 4, 5, 4, 2, 10, PreferFront, 0.6},
+		{"row end in the front row", 0, 5, 0, 2, 10, PreferFront, 0.467},
+		{"centered near the default ideal row", 2, 5, 4, 2, 10, PreferCenter, 0.96},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := score(tt.row, tt.rows, tt.start, tt.size, tt.cols, tt.pref); got != tt.want {
+				t.Errorf("score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvoidOrphans(t *testing.T) {
+	seats := grid("x....")
+	strands := Block{Row: 0, Seats: []lock.Seat{{Row: 0, Col: 2}, {Row: 0, Col: 3}}, Labels: []string{"A3", "A4"}}
+	clean := Block{Row: 0, Seats: []lock.Seat{{Row: 0, Col: 1}, {Row: 0, Col: 2}}, Labels: []string{"A2", "A3"}}
+	tests := []struct {
+		name       string
+		aisleAfter []int
+		keep       bool
+		want       []string
+	}{
+		{"stranding block dropped", nil, false, []string{"A2 A3"}},
+		{"stranding block ranked last", nil, true, []string{"A2 A3", "A3 A4"}},
+		{"seat beside an aisle is not stranded", []int{0}, false, []string{"A3 A4", "A2 A3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := blockLabels(AvoidOrphans(seats, []Block{strands, clean}, tt.aisleAfter, tt.keep))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AvoidOrphans() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package seating
+
+import (
+	"sort"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+)
+
+// ReasonOrphanSeat is the machine-readable reason for a selection that strands a single seat.
+const ReasonOrphanSeat = "ORPHAN_SEAT"
+
+// OrphanSeats returns the free seats that selection would newly leave isolated: a single AVAILABLE seat
+// with taken seats on both sides. Seats at a row end, next to an aisle (aisleAfter) or next to a non-seat
+// layout cell are exempt. The result is ordered by row, then column.
+func OrphanSeats(seats [][]model.Seat, selection []lock.Seat, aisleAfter []int) []lock.Seat {
+	aisle := make(map[int]bool, len(aisleAfter))
+	for _, c := range aisleAfter {
+		aisle[c] = true
+	}
+	picked := make(map[lock.Seat]bool, len(selection))
+	rows := make(map[int]bool)
+	for _, s := range selection {
+		picked[s] = true
+		rows[s.Row] = true
+	}
+	var out []lock.Seat
+	for r := range rows {
+		if r < 0 || r >= len(seats) {
+			continue
+		}
+		row := seats[r]
+		freeBefore := func(c int) bool { return row[c].Status == model.SeatAvailable }
+		freeAfter := func(c int) bool { return freeBefore(c) && !picked[lock.Seat{Row: r, Col: c}] }
+		for c := range row {
+			if !freeAfter(c) || isEdge(row, c, aisle) {
+				continue
+			}
+			if freeAfter(c-1) || freeAfter(c+1) {
+				continue
+			}
+			// Already stranded before this selection: not caused by it
+			if !freeBefore(c-1) && !freeBefore(c+1) {
+				continue
+			}
+			out = append(out, lock.Seat{Row: r, Col: c})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Row != out[j].Row {
+			return out[i].Row < out[j].Row
+		}
+		return out[i].Col < out[j].Col
+	})
+	return out
+}
+
+// isEdge reports whether col c sits at a row end or beside an aisle.
+func isEdge(row []model.Seat, c int, aisle map[int]bool) bool {
+	if c == 0 || c == len(row)-1 || aisle[c] || aisle[c-1] {
+		return true
+	}
+	return row[c-1].Status == model.SeatNone || row[c+1].Status == model.SeatNone
+}
@@ -0,0 +1,65 @@
+package seating
+
+import (
+	"reflect"
+	"testing"
+
+	"cinema-booking/internal/lock"
+)
+
+func TestOrphanSeats(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       []string
+		selection  []lock.Seat
+		aisleAfter []int
+		want       []lock.Seat
+	}{
+		{"single gap between two holds", []string{"......"}, []lock.Seat{{Row: 0, Col: 1}, {Row: 0, Col: 3}}, nil, []lock.Seat{{Row: 0, Col: 2}}},
+		{"adjacent holds leave no gap", []string{"......"}, []lock.Seat{{Row: 0, Col: 2}, {Row: 0, Col: 3}}, nil, nil},
+		{"gap at the row start", []string{"....."}, []lock.Seat{{Row: 0, Col: 1}}, nil, nil},
+		{"gap at the row end", []string{"....."}, []lock.Seat{{Row: 0, Col: 3}}, nil, nil},
+		{"gap after an aisle", []string{"......"}, []lock.Seat{{Row: 0, Col: 1}, {Row: 0, Col: 3}}, []int{1}, nil},
+		{"gap before an aisle", []string{"......"}, []lock.Seat{{Row: 0, Col: 1}, {Row: 0, Col: 3}}, []int{2}, nil},
+		{"gap beside a non-seat cell", []string{"x._.."}, []lock.Seat{{Row: 0, Col: 3}}, nil, nil},
+		{"gap next to a booked seat", []string{"x...."}, []lock.Seat{{Row: 0, Col: 2}}, nil, []lock.Seat{{Row: 0, Col: 1}}},
+		{"seat stranded before the selection", []string{"x.x..."}, []lock.Seat{{Row: 0, Col: 4}}, nil, []lock.Seat{{Row: 0, Col: 3}}},
+		{"several selections in one row", []string{"........"}, []lock.Seat{{Row: 0, Col: 5}, {Row: 0, Col: 1}, {Row: 0, Col: 3}}, nil, []lock.Seat{{Row: 0, Col: 2}, {Row: 0, Col: 4}}},
+		{"sorted by row then column", []string{"......", "......"}, []lock.Seat{{Row: 1, Col: 3}, {Row: 1, Col: 1}, {Row: 0, Col: 3}, {Row: 0, Col: 1}}, nil, []lock.Seat{{Row: 0, Col: 2}, {Row: 1, Col: 2}}},
+		{"row outside the seat map", []string{"...."}, []lock.Seat{{Row: 3, Col: 1}}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OrphanSeats(grid(tt.rows...), tt.selection, tt.aisleAfter)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OrphanSeats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsEdge(t *testing.T) {
+	row := grid("..._...")[0]
+	tests := []struct {
+		name  string
+		col   int
+		aisle map[int]bool
+		want  bool
+	}{
+		{"row start", 0, nil, true},
+		{"row end", 6, nil, true},
+		{"middle", 1, nil, false},
+		{"before an aisle", 1, map[int]bool{1: true}, true},
+		{"after an aisle", 1, map[int]bool{0: true}, true},
+		{"aisle elsewhere", 1, map[int]bool{4: true}, false},
+		{"before a non-seat cell", 2, nil, true},
+		{"after a non-seat cell", 4, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isEdge(row, tt.col, tt.aisle); got != tt.want {
+				t.Errorf("isEdge(%d) = %v, want %v", tt.col, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package seatmap
+
+import (
+	"context"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/repository"
+)
+
+// Grid builds the full Rows x Cols seat map of a screening, including live Redis locks.
+// Locks of PENDING bookings are read in a single MGET rather than one GET per seat.
+func Grid(ctx context.Context, repo *repository.MongoRepo, locks *lock.Manager, s *model.Screening) [][]model.Seat {
+	id := s.ID.Hex()
+	bookings, _ := repo.ListBookings(ctx, map[string]interface{}{"screening_id": id})
+	var pending []lock.Seat
+	for _, b := range bookings {
+		if b.Status == "PENDING" && b.LockID != "" {
+			pending = append(pending, lock.Seat{Row: b.SeatRow, Col: b.SeatCol})
+		}
+	}
+	held, _ := locks.GetLockIDs(ctx, id, pending)
+	seats := make([][]model.Seat, s.Rows)
+	for r := 0; r < s.Rows; r++ {
+		seats[r] = s.RowSeats(r)
+	}
+	for _, b := range bookings {
+		if b.SeatRow < 0 || b.SeatRow >= s.Rows || b.SeatCol < 0 || b.SeatCol >= s.Cols {
+			continue
+		}
+		st := &seats[b.SeatRow][b.SeatCol]
+		switch {
+		case st.Status == model.SeatBooked:
+		case b.Status == "CONFIRMED":
+			st.Status, st.UserID, st.LockID = model.SeatBooked, b.UserID, ""
+		case st.Status == model.SeatAvailable && b.Status == "PENDING" && b.LockID != "" &&
+			held[lock.Seat{Row: b.SeatRow, Col: b.SeatCol}] == b.LockID:
+			st.Status, st.UserID, st.LockID = model.SeatLocked, b.UserID, b.LockID
+		}
+	}
+	return seats
+}
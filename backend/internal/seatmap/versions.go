@@ -0,0 +1,132 @@
+package seatmap
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/ws"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	versionPrefix = "seatmap_version:"
+	changesPrefix = "seatmap_changes:"
+	floorPrefix   = "seatmap_floor:"
+	// keepChanges bounds the change log per screening; clients further behind must re-fetch the full map.
+	keepChanges = 1000
+)
+
+// publishScript bumps the version and appends each seat to the change log under it, trimming the oldest
+// entries and remembering the highest trimmed version as the floor below which deltas are incomplete.
+// KEYS: version, changes, floor. ARGV: keep, seat JSON...
+var publishScript = redis.NewScript(`
+local v = redis.call('INCR', KEYS[1])
+for i = 2, #ARGV do
+  redis.call('ZADD', KEYS[2], v, v .. '|' .. ARGV[i])
+end
+local keep = tonumber(ARGV[1])
+local cut = redis.call('ZRANGE', KEYS[2], 0, -(keep + 1), 'WITHSCORES')
+if #cut > 0 then
+  redis.call('SET', KEYS[3], cut[#cut])
+  redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -(keep + 1))
+end
+return v
+`)
+
+// Versions numbers seat map changes per screening. Every seat broadcast goes through Publish, which bumps a
+// Redis counter shared by all replicas, logs the changed seats under the new version and sends them to the
+// screening room with it, so clients can spot a gap and fetch just the missed changes.
+type Versions struct {
+	client *redis.Client
+	hub    *ws.Hub
+}
+
+func NewVersions(client *redis.Client, hub *ws.Hub) *Versions {
+	return &Versions{client: client, hub: hub}
+}
+
+// Current returns the screening's seat map version (0 before the first change).
+func (v *Versions) Current(ctx context.Context, screeningID string) (int64, error) {
+	n, err := v.client.Get(ctx, versionPrefix+screeningID).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Publish records seats as the next version of the screening's seat map and broadcasts them with it.
+// If Redis is unavailable the seats are still broadcast, without a version.
+func (v *Versions) Publish(ctx context.Context, screeningID string, seats ...model.Seat) int64 {
+	args := make([]interface{}, 0, len(seats)+1)
+	args = append(args, keepChanges)
+	for _, s := range seats {
+		b, err := json.Marshal(s)
+		if err != nil {
+			continue
+		}
+		args = append(args, string(b))
+	}
+	version, err := publishScript.Run(ctx, v.client,
+		[]string{versionPrefix + screeningID, changesPrefix + screeningID, floorPrefix + screeningID},
+		args...).Int64()
+	if err != nil {
+		log.Printf("seatmap: publish %s: %v", screeningID, err)
+		version = 0
+	}
+	v.hub.BroadcastSeatUpdate("screening:"+screeningID, version, seats)
+	return version
+}
+
+// Since returns the current version and the latest state of every seat changed after version since.
+// ok is false when the log no longer reaches back that far and the client must re-fetch the full map.
+func (v *Versions) Since(ctx context.Context, screeningID string, since int64) (current int64, changes []model.Seat, ok bool, err error) {
+	current, err = v.Current(ctx, screeningID)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if since > current || since < 0 {
+		return current, nil, false, nil
+	}
+	if since == current {
+		return current, []model.Seat{}, true, nil
+	}
+	floor, err := v.client.Get(ctx, floorPrefix+screeningID).Int64()
+	if err != nil && err != redis.Nil {
+		return current, nil, false, err
+	}
+	if since < floor {
+		return current, nil, false, nil
+	}
+	entries, err := v.client.ZRangeByScore(ctx, changesPrefix+screeningID, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(since, 10),
+		Max: strconv.FormatInt(current, 10),
+	}).Result()
+	if err != nil {
+		return current, nil, false, err
+	}
+	// Entries come in version order; keep only the last state of each seat.
+	index := make(map[[2]int]int)
+	changes = []model.Seat{}
+	for _, e := range entries {
+		i := strings.IndexByte(e, '|')
+		if i < 0 {
+			continue
+		}
+		var s model.Seat
+		if err := json.Unmarshal([]byte(e[i+1:]), &s); err != nil {
+			continue
+		}
+		k := [2]int{s.Row, s.Col}
+		if j, seen := index[k]; seen {
+			changes[j] = s
+			continue
+		}
+		index[k] = len(changes)
+		changes = append(changes, s)
+	}
+	return current, changes, true, nil
+}
@@ -25,33 +25,48 @@ func Run(ctx context.Context, repo *repository.MongoRepo) {
 	log.Println("seed: first run — inserting seed data")
 
 	now := time.Now()
+	movies := []*model.Movie{
+		{ID: primitive.NewObjectID(), Title: "The Matrix", RuntimeMinutes: 136, AgeRating: "R", Genres: []string{"Sci-Fi", "Action"}, Languages: []string{"EN"}},
+		{ID: primitive.NewObjectID(), Title: "Inception", RuntimeMinutes: 148, AgeRating: "PG-13", Genres: []string{"Sci-Fi", "Thriller"}, Languages: []string{"EN"}},
+		{ID: primitive.NewObjectID(), Title: "Interstellar", RuntimeMinutes: 169, AgeRating: "PG-13", Genres: []string{"Sci-Fi", "Drama"}, Languages: []string{"EN"}},
+	}
+	for _, m := range movies {
+		if err := repo.CreateMovie(ctx, m); err != nil {
+			log.Printf("seed: create movie %s: %v", m.Title, err)
+			return
+		}
+	}
+
 	screenings := []*model.Screening{
 		{
-			ID:        primitive.NewObjectID(),
-			MovieID:   "mv-001",
-			MovieName: "The Matrix",
-			ScreenAt:  now.Add(24 * time.Hour),
-			Rows:      5,
-			Cols:      8,
-			CreatedAt: now,
+			ID:             primitive.NewObjectID(),
+			MovieID:        movies[0].ID.Hex(),
+			MovieName:      movies[0].Title,
+			ScreenAt:       now.Add(24 * time.Hour),
+			Rows:           5,
+			Cols:           8,
+			CreatedAt:      now,
+			RuntimeMinutes: movies[0].RuntimeMinutes,
 		},
 		{
-			ID:        primitive.NewObjectID(),
-			MovieID:   "mv-002",
-			MovieName: "Inception",
-			ScreenAt:  now.Add(48 * time.Hour),
-			Rows:      6,
-			Cols:      10,
-			CreatedAt: now,
+			ID:             primitive.NewObjectID(),
+			MovieID:        movies[1].ID.Hex(),
+			MovieName:      movies[1].Title,
+			ScreenAt:       now.Add(48 * time.Hour),
+			Rows:           6,
+			Cols:           10,
+			CreatedAt:      now,
+			RuntimeMinutes: movies[1].RuntimeMinutes,
 		},
 		{
-			ID:        primitive.NewObjectID(),
-			MovieID:   "mv-003",
-			MovieName: "Interstellar",
-			ScreenAt:  now.Add(72 * time.Hour),
-			Rows:      5,
-			Cols:      8,
-			CreatedAt: now,
+			ID:             primitive.NewObjectID(),
+			MovieID:        movies[2].ID.Hex(),
+			MovieName:      movies[2].Title,
+			ScreenAt:       now.Add(72 * time.Hour),
+			Rows:           5,
+			Cols:           8,
+			CreatedAt:      now,
+			RuntimeMinutes: movies[2].RuntimeMinutes,
 		},
 	}
 
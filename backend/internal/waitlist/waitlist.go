@@ -0,0 +1,208 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/quota"
+	"cinema-booking/internal/repository"
+	"cinema-booking/internal/seating"
+	"cinema-booking/internal/seatmap"
+	"cinema-booking/internal/ws"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "waitlist:"
+
+// Service keeps a FIFO waitlist per screening in Redis and offers released seats to the first user in line.
+// An offer is a PENDING booking held under a seat lock for offerTTL; if it is not confirmed it times out like
+// any hold, SEAT_RELEASED fires again and the seat rolls to the next user.
+// Offers follow the same rules as a hold: the screening must be on sale, the seat counts against the user's
+// quota and the orphan rule is applied as for a single-seat hold (REJECT skips the offer, WARN flags it).
+type Service struct {
+	client      *redis.Client
+	repo        *repository.MongoRepo
+	lock        *lock.Manager
+	hub         *ws.Hub
+	seats       *seatmap.Versions
+	quota       *quota.Limiter
+	offerTTL    time.Duration
+	salesCutoff time.Duration
+	onAudit     func(string, map[string]any)
+}
+
+func NewService(client *redis.Client, repo *repository.MongoRepo, lockMgr *lock.Manager, hub *ws.Hub, seats *seatmap.Versions, limiter *quota.Limiter, offerTTLSeconds int, salesCutoff time.Duration, onAudit func(string, map[string]any)) *Service {
+	return &Service{
+		client:      client,
+		repo:        repo,
+		lock:        lockMgr,
+		hub:         hub,
+		seats:       seats,
+		quota:       limiter,
+		offerTTL:    time.Duration(offerTTLSeconds) * time.Second,
+		salesCutoff: salesCutoff,
+		onAudit:     onAudit,
+	}
+}
+
+func (s *Service) key(screeningID string) string { return keyPrefix + screeningID }
+
+// Join adds userID to the end of the screening's waitlist (no-op if already waiting) and returns the 1-based position.
+func (s *Service) Join(ctx context.Context, screeningID, userID string) (int, error) {
+	k := s.key(screeningID)
+	if err := s.client.ZAddNX(ctx, k, redis.Z{Score: float64(time.Now().UnixMilli()), Member: userID}).Err(); err != nil {
+		return 0, err
+	}
+	return s.Position(ctx, screeningID, userID)
+}
+
+// Leave removes userID from the waitlist.
+func (s *Service) Leave(ctx context.Context, screeningID, userID string) error {
+	return s.client.ZRem(ctx, s.key(screeningID), userID).Err()
+}
+
+// Position returns the 1-based position of userID, or 0 if not waiting.
+func (s *Service) Position(ctx context.Context, screeningID, userID string) (int, error) {
+	rank, err := s.client.ZRank(ctx, s.key(screeningID), userID).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int(rank) + 1, nil
+}
+
+// OnSeatReleased offers a freed seat to the first waitlisted user. Every replica receives the MQ event;
+// the seat lock makes sure only one of them creates the offer.
+func (s *Service) OnSeatReleased(ctx context.Context, screeningID string, row, col int) {
+	k := s.key(screeningID)
+	if n, _ := s.client.ZCard(ctx, k).Result(); n == 0 {
+		return
+	}
+	sc, err := s.repo.GetScreening(ctx, screeningID)
+	if err != nil {
+		log.Printf("waitlist: screening %s: %v", screeningID, err)
+		return
+	}
+	if sc.SaleStateAt(time.Now(), s.salesCutoff) != model.SaleOnSale {
+		return // paused or closed: the seat stays free and the line keeps its order
+	}
+	if !sc.IsSeat(row, col) {
+		return
+	}
+	seat := []lock.Seat{{Row: row, Col: col}}
+	var orphans []lock.Seat
+	if rule := sc.SeatingRule; rule != nil && (rule.OrphanSeat == model.OrphanRuleWarn || rule.OrphanSeat == model.OrphanRuleReject) {
+		orphans = seating.OrphanSeats(seatmap.Grid(ctx, s.repo, s.lock, sc), seat, rule.AisleAfter)
+		if len(orphans) > 0 && rule.OrphanSeat == model.OrphanRuleReject {
+			return
+		}
+	}
+	// A release can race a confirm, and a refund event can be replayed after the seat was sold again
+	if sold, err := s.repo.CountSoldSeats(ctx, screeningID, seat); err != nil || sold > 0 {
+		return
+	}
+	lockID, err := s.lock.AcquireFor(ctx, screeningID, row, col, s.offerTTL)
+	if err != nil {
+		log.Printf("waitlist: lock %s %d:%d: %v", screeningID, row, col, err)
+		return
+	}
+	if lockID == "" {
+		return // someone (or another replica) already took the seat
+	}
+	// Checked again under the lock, as in holdSeats: a confirm writes CONFIRMED before releasing its lock
+	if sold, err := s.repo.CountSoldSeats(ctx, screeningID, seat); err != nil || sold > 0 {
+		_ = s.lock.Release(ctx, screeningID, row, col, lockID)
+		return
+	}
+	now := time.Now()
+	expiresAt := now.Add(s.offerTTL)
+	entry, ok := s.nextWithinQuota(ctx, screeningID, seat, expiresAt)
+	if !ok {
+		_ = s.lock.Release(ctx, screeningID, row, col, lockID)
+		return
+	}
+	userID := fmt.Sprint(entry.Member)
+	offered := sc.SeatAt(row, col)
+	b := &model.Booking{
+		ScreeningID:   screeningID,
+		UserID:        userID,
+		SeatRow:       row,
+		SeatCol:       col,
+		SeatLabel:     offered.Label,
+		Status:        "PENDING",
+		LockID:        lockID,
+		LockExpiresAt: &expiresAt,
+		WaitlistOffer: true,
+		SeatCategory:  offered.Category,
+		Price:         offered.Price,
+		CreatedAt:     now,
+	}
+	if err := s.repo.CreateBooking(ctx, b); err != nil {
+		log.Printf("waitlist: create offer booking: %v", err)
+		_ = s.lock.Release(ctx, screeningID, row, col, lockID)
+		_ = s.quota.Release(ctx, screeningID, userID, seat)
+		// Put the user back at the front of the line
+		s.client.ZAdd(ctx, k, entry)
+		return
+	}
+	payload := map[string]any{
+		"booking_id":   b.ID.Hex(),
+		"screening_id": screeningID,
+		"user_id":      userID,
+		"seat_row":     row,
+		"seat_col":     col,
+		"seat_label":   offered.Label,
+		"price":        offered.Price,
+		"expires_at":   expiresAt,
+	}
+	if len(orphans) > 0 {
+		payload["warnings"] = []map[string]any{{"reason": seating.ReasonOrphanSeat, "orphan_seats": orphans}}
+	}
+	if s.onAudit != nil {
+		s.onAudit(model.EventWaitlistOffer, payload)
+	}
+	s.hub.NotifyUser(userID, model.EventWaitlistOffer, payload)
+	offered.Status, offered.LockID, offered.UserID = model.SeatLocked, lockID, userID
+	s.seats.Publish(ctx, screeningID, offered)
+	s.hub.BroadcastAdmin("REFRESH", nil)
+}
+
+// nextWithinQuota pops users off the front of the waitlist until one can hold seat within the seat quota and
+// reserves it for them. Users already at their quota keep their place for a later seat.
+func (s *Service) nextWithinQuota(ctx context.Context, screeningID string, seat []lock.Seat, expiresAt time.Time) (redis.Z, bool) {
+	k := s.key(screeningID)
+	var skipped []redis.Z
+	defer func() {
+		if len(skipped) > 0 {
+			s.client.ZAdd(ctx, k, skipped...)
+		}
+	}()
+	for {
+		popped, err := s.client.ZPopMin(ctx, k, 1).Result()
+		if err != nil || len(popped) == 0 {
+			return redis.Z{}, false
+		}
+		userID := fmt.Sprint(popped[0].Member)
+		booked, err := s.repo.CountBookings(ctx, map[string]interface{}{"screening_id": screeningID, "user_id": userID, "status": "CONFIRMED"})
+		if err != nil {
+			skipped = append(skipped, popped[0])
+			return redis.Z{}, false
+		}
+		ok, _, err := s.quota.Reserve(ctx, screeningID, userID, seat, booked, expiresAt)
+		if err != nil {
+			log.Printf("waitlist: quota %s %s: %v", screeningID, userID, err)
+			skipped = append(skipped, popped[0])
+			return redis.Z{}, false
+		}
+		if ok {
+			return popped[0], true
+		}
+		skipped = append(skipped, popped[0])
+	}
+}
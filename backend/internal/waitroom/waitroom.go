@@ -0,0 +1,268 @@
+package waitroom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/ws"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix  = "waitroom:"
+	activeKey  = "waitroom:active"  // screenings with a non-empty queue or admitted set
+	enabledKey = "waitroom:enabled" // screenings whose waiting room is on, mirrored from Mongo for the admission check
+)
+
+const (
+	StateNone     = "NONE"
+	StateWaiting  = "WAITING"
+	StateAdmitted = "ADMITTED"
+)
+
+// Status is one user's place in a screening's waiting room.
+type Status struct {
+	State     string     `json:"state"`
+	Position  int        `json:"position,omitempty"` // 1-based, while WAITING
+	QueueSize int        `json:"queue_size"`
+	Token     string     `json:"admission_token,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// admitScript drops expired admissions, then moves users from the head of the queue into the admitted set
+// until it holds capacity users. KEYS: queue, admitted. ARGV: now ms, capacity, admission expiry ms.
+var admitScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+local free = tonumber(ARGV[2]) - redis.call('ZCARD', KEYS[2])
+if free <= 0 then return {} end
+local popped = redis.call('ZPOPMIN', KEYS[1], free)
+local users = {}
+for i = 1, #popped, 2 do
+  redis.call('ZADD', KEYS[2], ARGV[3], popped[i])
+  table.insert(users, popped[i])
+end
+return users
+`)
+
+// Room is a per-screening admission queue in Redis. Users join a FIFO queue and at most capacity of them are
+// admitted at a time; an admitted user gets a token that is valid for admitTTL and is required to load the
+// seat map and lock seats. State lives in Redis, so every replica admits from the same queue.
+type Room struct {
+	client   *redis.Client
+	hub      *ws.Hub
+	capacity int
+	admitTTL time.Duration
+}
+
+func NewRoom(client *redis.Client, hub *ws.Hub, capacity, admitSeconds int) *Room {
+	return &Room{
+		client:   client,
+		hub:      hub,
+		capacity: capacity,
+		admitTTL: time.Duration(admitSeconds) * time.Second,
+	}
+}
+
+func (r *Room) queueKey(screeningID string) string    { return keyPrefix + screeningID + ":queue" }
+func (r *Room) seqKey(screeningID string) string      { return keyPrefix + screeningID + ":seq" }
+func (r *Room) admittedKey(screeningID string) string { return keyPrefix + screeningID + ":admitted" }
+func (r *Room) tokenKey(screeningID, userID string) string {
+	return fmt.Sprintf("%s%s:token:%s", keyPrefix, screeningID, userID)
+}
+
+// Join puts userID at the end of the queue (no-op if already queued or admitted), admits whoever fits and
+// returns the user's status.
+func (r *Room) Join(ctx context.Context, screeningID, userID string) (*Status, error) {
+	now := time.Now()
+	admitted, err := r.client.ZScore(ctx, r.admittedKey(screeningID), userID).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if err == redis.Nil || admitted <= float64(now.UnixMilli()) {
+		// A shared counter orders joins exactly across replicas; clock readings would not, and UnixNano does
+		// not fit a float64 score
+		seq, err := r.client.Incr(ctx, r.seqKey(screeningID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if err := r.client.ZAddNX(ctx, r.queueKey(screeningID), redis.Z{Score: float64(seq), Member: userID}).Err(); err != nil {
+			return nil, err
+		}
+		r.client.SAdd(ctx, activeKey, screeningID)
+	}
+	if _, err := r.admit(ctx, screeningID); err != nil {
+		return nil, err
+	}
+	return r.Status(ctx, screeningID, userID)
+}
+
+// SetEnabled records whether the screening's waiting room is on.
+func (r *Room) SetEnabled(ctx context.Context, screeningID string, enabled bool) error {
+	if enabled {
+		return r.client.SAdd(ctx, enabledKey, screeningID).Err()
+	}
+	return r.client.SRem(ctx, enabledKey, screeningID).Err()
+}
+
+// Enabled reports whether the screening's waiting room is on.
+func (r *Room) Enabled(ctx context.Context, screeningID string) (bool, error) {
+	return r.client.SIsMember(ctx, enabledKey, screeningID).Result()
+}
+
+// SyncEnabled replaces the set of screenings with a waiting room, e.g. from Mongo at startup.
+func (r *Room) SyncEnabled(ctx context.Context, screeningIDs []string) error {
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, enabledKey)
+	for _, id := range screeningIDs {
+		pipe.SAdd(ctx, enabledKey, id)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Leave removes userID from the queue and gives up any admission, freeing the slot for the next user.
+func (r *Room) Leave(ctx context.Context, screeningID, userID string) error {
+	pipe := r.client.TxPipeline()
+	pipe.ZRem(ctx, r.queueKey(screeningID), userID)
+	pipe.ZRem(ctx, r.admittedKey(screeningID), userID)
+	pipe.Del(ctx, r.tokenKey(screeningID, userID))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Status reports whether userID is waiting (with position) or admitted (with token).
+func (r *Room) Status(ctx context.Context, screeningID, userID string) (*Status, error) {
+	size, err := r.client.ZCard(ctx, r.queueKey(screeningID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	st := &Status{State: StateNone, QueueSize: int(size)}
+	score, err := r.client.ZScore(ctx, r.admittedKey(screeningID), userID).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if err == nil {
+		expiresAt := time.UnixMilli(int64(score))
+		if expiresAt.After(time.Now()) {
+			token, err := r.token(ctx, screeningID, userID, expiresAt)
+			if err != nil {
+				return nil, err
+			}
+			st.State, st.Token, st.ExpiresAt = StateAdmitted, token, &expiresAt
+			return st, nil
+		}
+	}
+	rank, err := r.client.ZRank(ctx, r.queueKey(screeningID), userID).Result()
+	if err == redis.Nil {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	st.State, st.Position = StateWaiting, int(rank)+1
+	return st, nil
+}
+
+// Admitted reports whether token is the live admission token of userID for the screening.
+func (r *Room) Admitted(ctx context.Context, screeningID, userID, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	got, err := r.client.Get(ctx, r.tokenKey(screeningID, userID)).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return got == token, nil
+}
+
+// token returns the user's admission token, creating it if the admitting replica has not written it yet.
+func (r *Room) token(ctx context.Context, screeningID, userID string, expiresAt time.Time) (string, error) {
+	k := r.tokenKey(screeningID, userID)
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return "", nil
+	}
+	if err := r.client.SetNX(ctx, k, uuid.New().String(), ttl).Err(); err != nil {
+		return "", err
+	}
+	return r.client.Get(ctx, k).Result()
+}
+
+// admit moves users from the queue into free admission slots and notifies them with their token.
+func (r *Room) admit(ctx context.Context, screeningID string) (int, error) {
+	now := time.Now()
+	expiresAt := now.Add(r.admitTTL)
+	res, err := admitScript.Run(ctx, r.client,
+		[]string{r.queueKey(screeningID), r.admittedKey(screeningID)},
+		now.UnixMilli(), r.capacity, expiresAt.UnixMilli()).StringSlice()
+	if err != nil {
+		return 0, err
+	}
+	for _, userID := range res {
+		token, err := r.token(ctx, screeningID, userID, expiresAt)
+		if err != nil {
+			log.Printf("waitroom: token %s %s: %v", screeningID, userID, err)
+			continue
+		}
+		r.hub.NotifyUser(userID, model.EventQueueAdmitted, map[string]any{
+			"screening_id":    screeningID,
+			"admission_token": token,
+			"expires_at":      expiresAt,
+		})
+	}
+	return len(res), nil
+}
+
+// Run admits users into free slots of every active room each interval and pushes queue positions to
+// waiting users connected to this replica.
+func (r *Room) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ids, err := r.client.SMembers(ctx, activeKey).Result()
+			if err != nil {
+				log.Printf("waitroom: active rooms: %v", err)
+				continue
+			}
+			for _, id := range ids {
+				r.tick(ctx, id)
+			}
+		}
+	}
+}
+
+func (r *Room) tick(ctx context.Context, screeningID string) {
+	if _, err := r.admit(ctx, screeningID); err != nil {
+		log.Printf("waitroom: admit %s: %v", screeningID, err)
+		return
+	}
+	waiting, err := r.client.ZRange(ctx, r.queueKey(screeningID), 0, -1).Result()
+	if err != nil {
+		log.Printf("waitroom: queue %s: %v", screeningID, err)
+		return
+	}
+	if len(waiting) == 0 {
+		if n, _ := r.client.ZCard(ctx, r.admittedKey(screeningID)).Result(); n == 0 {
+			r.client.SRem(ctx, activeKey, screeningID)
+		}
+		return
+	}
+	for i, userID := range waiting {
+		r.hub.NotifyUser(userID, model.EventQueuePosition, map[string]any{
+			"screening_id": screeningID,
+			"position":     i + 1,
+			"queue_size":   len(waiting),
+		})
+	}
+}
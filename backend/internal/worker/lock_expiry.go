@@ -9,49 +9,138 @@ import (
 	"cinema-booking/internal/model"
 	"cinema-booking/internal/mq"
 	"cinema-booking/internal/repository"
+	"cinema-booking/internal/seatmap"
 	"cinema-booking/internal/ws"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-const lockTTL = 5 * time.Minute
+// LockExpiry times out PENDING bookings whose seat lock is gone. Redis expired-key events drive it so a seat is
+// released within a second; a slow polling sweep stays as a safety net for missed events.
+type LockExpiry struct {
+	repo    *repository.MongoRepo
+	lockMgr *lock.Manager
+	pub     *mq.Publisher
+	hub     *ws.Hub
+	seats   *seatmap.Versions
+	onAudit func(string, map[string]any)
+	lockTTL time.Duration
+}
+
+func NewLockExpiry(repo *repository.MongoRepo, lockMgr *lock.Manager, pub *mq.Publisher, hub *ws.Hub, seats *seatmap.Versions, onAudit func(string, map[string]any), lockTTLSeconds int) *LockExpiry {
+	return &LockExpiry{
+		repo:    repo,
+		lockMgr: lockMgr,
+		pub:     pub,
+		hub:     hub,
+		seats:   seats,
+		onAudit: onAudit,
+		lockTTL: time.Duration(lockTTLSeconds) * time.Second,
+	}
+}
 
-// RunLockExpiry periodically marks expired PENDING bookings as TIMEOUT, releases Redis lock, audits, publishes and broadcasts.
-func RunLockExpiry(ctx context.Context, repo *repository.MongoRepo, lockMgr *lock.Manager, pub *mq.Publisher, hub *ws.Hub, onAudit func(string, map[string]any)) {
-	ticker := time.NewTicker(30 * time.Second)
+// RunEvents handles seat_lock expirations as Redis reports them. Blocks until ctx is done.
+func (e *LockExpiry) RunEvents(ctx context.Context) {
+	e.lockMgr.WatchExpired(ctx, func(screeningID string, seat lock.Seat) {
+		list, err := e.repo.ListBookings(ctx, bson.M{"screening_id": screeningID, "seat_row": seat.Row, "seat_col": seat.Col, "status": "PENDING"})
+		if err != nil {
+			log.Printf("lock_expiry: list: %v", err)
+			return
+		}
+		for _, b := range list {
+			// The seat may already be locked again by someone else; only the booking whose lock vanished expires
+			if cur, _ := e.lockMgr.GetLockID(ctx, b.ScreeningID, b.SeatRow, b.SeatCol); cur == b.LockID {
+				continue
+			}
+			e.expire(ctx, b)
+		}
+	})
+}
+
+// RunSweep periodically marks expired PENDING bookings as TIMEOUT, using the configured lock TTL. Like the event
+// path it leaves bookings whose Redis lock is still live: an extend may have reached Redis before Mongo, or the
+// clocks may disagree.
+func (e *LockExpiry) RunSweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			cutoff := time.Now().Add(-lockTTL)
-			list, err := repo.ListBookings(ctx, bson.M{"status": "PENDING", "created_at": bson.M{"$lt": cutoff}})
+			now := time.Now()
+			cutoff := now.Add(-e.lockTTL)
+			// Bookings carry lock_expires_at (moved forward on extend); older ones fall back to created_at + TTL.
+			list, err := e.repo.ListBookings(ctx, bson.M{"status": "PENDING", "$or": bson.A{
+				bson.M{"lock_expires_at": bson.M{"$lt": now}},
+				bson.M{"lock_expires_at": bson.M{"$exists": false}, "created_at": bson.M{"$lt": cutoff}},
+			}})
 			if err != nil {
 				log.Printf("lock_expiry: list: %v", err)
 				continue
 			}
-			for _, b := range list {
-				_ = lockMgr.Release(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
-				// Only set TIMEOUT if still PENDING (avoid overwriting CONFIRMED after race)
-				updated, _ := repo.SetBookingStatusIfPending(ctx, b.ID.Hex(), "TIMEOUT")
-				if !updated {
-					continue
-				}
-				if onAudit != nil {
-					onAudit(model.EventBookingTimeout, map[string]any{"booking_id": b.ID.Hex(), "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol})
-				}
-				_ = pub.PublishSeatReleased(ctx, b.ScreeningID, b.SeatRow, b.SeatCol)
-				bookings, _ := repo.ListBookings(ctx, bson.M{"screening_id": b.ScreeningID})
-				seat := seatStateFor(b.ScreeningID, bookings, b.SeatRow, b.SeatCol)
-				hub.BroadcastSeatUpdate("screening:"+b.ScreeningID, seat)
-				hub.BroadcastAdmin("REFRESH", nil)
+			for _, b := range e.lockGone(ctx, list) {
+				e.expire(ctx, b)
 			}
 		}
 	}
 }
 
-func seatStateFor(screeningID string, bookings []*model.Booking, row, col int) model.Seat {
+// lockGone returns the bookings of list whose seat is no longer held by their own lock, reading the locks of
+// each screening in one MGET.
+func (e *LockExpiry) lockGone(ctx context.Context, list []*model.Booking) []*model.Booking {
+	byScreening := make(map[string][]*model.Booking)
+	for _, b := range list {
+		byScreening[b.ScreeningID] = append(byScreening[b.ScreeningID], b)
+	}
+	var out []*model.Booking
+	for screeningID, bookings := range byScreening {
+		seats := make([]lock.Seat, len(bookings))
+		for i, b := range bookings {
+			seats[i] = lock.Seat{Row: b.SeatRow, Col: b.SeatCol}
+		}
+		held, err := e.lockMgr.GetLockIDs(ctx, screeningID, seats)
+		if err != nil {
+			log.Printf("lock_expiry: locks %s: %v", screeningID, err)
+			continue
+		}
+		for i, b := range bookings {
+			if held[seats[i]] != b.LockID {
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+// expire releases the Redis lock, sets TIMEOUT if still PENDING, audits, publishes and broadcasts.
+func (e *LockExpiry) expire(ctx context.Context, b *model.Booking) {
+	_ = e.lockMgr.Release(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
+	// Only set TIMEOUT if still PENDING (avoid overwriting CONFIRMED after race)
+	updated, _ := e.repo.SetBookingStatusIfPending(ctx, b.ID.Hex(), "TIMEOUT")
+	if !updated {
+		return
+	}
+	if b.OrderID != "" {
+		_, _ = e.repo.SyncOrderStatus(ctx, b.OrderID)
+	}
+	if e.onAudit != nil {
+		e.onAudit(model.EventBookingTimeout, map[string]any{"booking_id": b.ID.Hex(), "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol, "seat_label": b.SeatLabel})
+	}
+	_ = e.pub.PublishSeatReleased(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel)
+	s, _ := e.repo.GetScreening(ctx, b.ScreeningID)
+	bookings, _ := e.repo.ListBookings(ctx, bson.M{"screening_id": b.ScreeningID})
+	seat := seatStateFor(s, bookings, b.SeatRow, b.SeatCol)
+	e.seats.Publish(ctx, b.ScreeningID, seat)
+	e.hub.BroadcastAdmin("REFRESH", nil)
+}
+
+// seatStateFor is the seat's state once its lock is gone: BOOKED if confirmed, otherwise AVAILABLE.
+// s supplies category and price and may be nil if the screening could not be read.
+func seatStateFor(s *model.Screening, bookings []*model.Booking, row, col int) model.Seat {
 	st := model.Seat{Row: row, Col: col, Status: model.SeatAvailable}
+	if s != nil {
+		st = s.SeatAt(row, col)
+	}
 	for _, b := range bookings {
 		if b.SeatRow == row && b.SeatCol == col && b.Status == "CONFIRMED" {
 			st.Status = model.SeatBooked
@@ -0,0 +1,190 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cinema-booking/internal/lock"
+	"cinema-booking/internal/model"
+	"cinema-booking/internal/mq"
+	"cinema-booking/internal/repository"
+	"cinema-booking/internal/seatmap"
+	"cinema-booking/internal/ws"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ReconcileSummary describes one reconciliation pass between Redis seat locks and Mongo PENDING bookings.
+type ReconcileSummary struct {
+	RanAt          time.Time `json:"ran_at"`
+	PendingChecked int       `json:"pending_checked"`
+	LocksChecked   int       `json:"locks_checked"`
+	LocksRestored  int       `json:"locks_restored"`  // PENDING booking lost its Redis lock (e.g. Redis flush); lock re-created
+	BookingsLost   int       `json:"bookings_lost"`   // PENDING booking lost its lock and someone else holds the seat now
+	OrphanReleased int       `json:"orphan_released"` // Redis lock with no PENDING booking (crash between Acquire and CreateBooking)
+	Errors         int       `json:"errors"`
+}
+
+// Reconciler repairs drift between Redis locks and PENDING bookings. Every fix is audited.
+type Reconciler struct {
+	repo    *repository.MongoRepo
+	lockMgr *lock.Manager
+	pub     *mq.Publisher
+	hub     *ws.Hub
+	seats   *seatmap.Versions
+	onAudit func(string, map[string]any)
+	lockTTL time.Duration
+
+	mu      sync.Mutex
+	last    ReconcileSummary
+	suspect map[lock.HeldLock]bool // orphan locks seen in the previous pass
+}
+
+func NewReconciler(repo *repository.MongoRepo, lockMgr *lock.Manager, pub *mq.Publisher, hub *ws.Hub, seats *seatmap.Versions, onAudit func(string, map[string]any), lockTTLSeconds int) *Reconciler {
+	return &Reconciler{
+		repo:    repo,
+		lockMgr: lockMgr,
+		pub:     pub,
+		hub:     hub,
+		seats:   seats,
+		onAudit: onAudit,
+		lockTTL: time.Duration(lockTTLSeconds) * time.Second,
+		suspect: make(map[lock.HeldLock]bool),
+	}
+}
+
+// Run reconciles every interval until ctx is done.
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.RunOnce(ctx)
+		}
+	}
+}
+
+// Last returns the summary of the most recent pass.
+func (r *Reconciler) Last() ReconcileSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// RunOnce performs one reconciliation pass and returns its summary.
+func (r *Reconciler) RunOnce(ctx context.Context) ReconcileSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sum := ReconcileSummary{RanAt: time.Now()}
+
+	pending, err := r.repo.ListBookings(ctx, bson.M{"status": "PENDING"})
+	if err != nil {
+		log.Printf("reconcile: list pending: %v", err)
+		sum.Errors++
+		r.last = sum
+		return sum
+	}
+	locks, err := r.lockMgr.ListLocks(ctx)
+	if err != nil {
+		log.Printf("reconcile: list locks: %v", err)
+		sum.Errors++
+		r.last = sum
+		return sum
+	}
+	sum.PendingChecked = len(pending)
+	sum.LocksChecked = len(locks)
+
+	held := make(map[lock.HeldLock]bool, len(locks))
+	for _, l := range locks {
+		held[l] = true
+	}
+	wanted := make(map[lock.HeldLock]bool, len(pending))
+	now := time.Now()
+	for _, b := range pending {
+		hl := lock.HeldLock{ScreeningID: b.ScreeningID, Seat: lock.Seat{Row: b.SeatRow, Col: b.SeatCol}, LockID: b.LockID}
+		wanted[hl] = true
+		if held[hl] {
+			continue
+		}
+		expiresAt := b.CreatedAt.Add(r.lockTTL)
+		if b.LockExpiresAt != nil {
+			expiresAt = *b.LockExpiresAt
+		}
+		if !expiresAt.After(now) {
+			continue // already expired: the lock expiry worker times it out
+		}
+		restored, err := r.lockMgr.Restore(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID, expiresAt.Sub(now))
+		if err != nil {
+			sum.Errors++
+			continue
+		}
+		if restored {
+			// pending was read before the locks: a booking cancelled or confirmed in between looks like a lost
+			// lock, and the seat must not stay blocked by the restored one
+			if cur, err := r.repo.GetBookingByID(ctx, b.ID.Hex()); err != nil || cur.Status != "PENDING" || cur.LockID != b.LockID {
+				_ = r.lockMgr.Release(ctx, b.ScreeningID, b.SeatRow, b.SeatCol, b.LockID)
+				continue
+			}
+			sum.LocksRestored++
+			r.audit("LOCK_RESTORED", b.ID.Hex(), b.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel, b.LockID)
+			continue
+		}
+		// Seat is held by another lock now: this hold cannot be honored
+		updated, _ := r.repo.SetBookingStatusIfPending(ctx, b.ID.Hex(), "TIMEOUT")
+		if updated {
+			if b.OrderID != "" {
+				_, _ = r.repo.SyncOrderStatus(ctx, b.OrderID)
+			}
+			sum.BookingsLost++
+			r.audit("BOOKING_LOCK_LOST", b.ID.Hex(), b.ScreeningID, b.SeatRow, b.SeatCol, b.SeatLabel, b.LockID)
+			r.hub.NotifyUser(b.UserID, model.EventBookingTimeout, map[string]any{"booking_id": b.ID.Hex(), "screening_id": b.ScreeningID, "seat_row": b.SeatRow, "seat_col": b.SeatCol, "seat_label": b.SeatLabel})
+		}
+	}
+
+	// A lock without a booking may belong to a request still between Acquire and CreateBooking,
+	// so only release it when it is seen orphaned on two consecutive passes.
+	suspect := make(map[lock.HeldLock]bool)
+	for _, l := range locks {
+		if wanted[l] {
+			continue
+		}
+		if !r.suspect[l] {
+			suspect[l] = true
+			continue
+		}
+		if err := r.lockMgr.Release(ctx, l.ScreeningID, l.Seat.Row, l.Seat.Col, l.LockID); err != nil {
+			sum.Errors++
+			continue
+		}
+		sum.OrphanReleased++
+		s, _ := r.repo.GetScreening(ctx, l.ScreeningID)
+		bookings, _ := r.repo.ListBookings(ctx, bson.M{"screening_id": l.ScreeningID})
+		seat := seatStateFor(s, bookings, l.Seat.Row, l.Seat.Col)
+		r.audit("ORPHAN_LOCK_RELEASED", "", l.ScreeningID, l.Seat.Row, l.Seat.Col, seat.Label, l.LockID)
+		_ = r.pub.PublishSeatReleased(ctx, l.ScreeningID, l.Seat.Row, l.Seat.Col, seat.Label)
+		r.seats.Publish(ctx, l.ScreeningID, seat)
+	}
+	r.suspect = suspect
+
+	if sum.LocksRestored+sum.BookingsLost+sum.OrphanReleased > 0 {
+		log.Printf("reconcile: %+v", sum)
+		r.hub.BroadcastAdmin("REFRESH", nil)
+	}
+	r.last = sum
+	return sum
+}
+
+func (r *Reconciler) audit(fix, bookingID, screeningID string, row, col int, label, lockID string) {
+	if r.onAudit == nil {
+		return
+	}
+	payload := map[string]any{"fix": fix, "screening_id": screeningID, "seat_row": row, "seat_col": col, "seat_label": label, "lock_id": lockID}
+	if bookingID != "" {
+		payload["booking_id"] = bookingID
+	}
+	r.onAudit(model.EventReconcileFix, payload)
+}
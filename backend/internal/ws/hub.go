@@ -9,6 +9,7 @@ import (
 type Message struct {
 	Type    string      `json:"type"`
 	Payload interface{} `json:"payload"`
+	Version int64       `json:"version,omitempty"` // seat map version, on SEAT_UPDATE
 }
 
 type Client struct {
@@ -18,16 +19,17 @@ type Client struct {
 }
 
 type Hub struct {
-	mu      sync.RWMutex
-	rooms   map[string]map[*Client]struct{}
-	broadcast chan roomMessage
-	register  chan *Client
+	mu         sync.RWMutex
+	rooms      map[string]map[*Client]struct{}
+	broadcast  chan roomMessage
+	register   chan *Client
 	unregister chan *Client
 }
 
 type roomMessage struct {
-	Room string
-	Msg  []byte
+	Room   string
+	UserID string // if set, deliver only to this user's clients (in any room) instead of Room
+	Msg    []byte
 }
 
 func NewHub() *Hub {
@@ -63,6 +65,21 @@ func (h *Hub) Run() {
 
 		case rm := <-h.broadcast:
 			h.mu.RLock()
+			if rm.UserID != "" {
+				for _, m := range h.rooms {
+					for c := range m {
+						if c.UserID != rm.UserID {
+							continue
+						}
+						select {
+						case c.Send <- rm.Msg:
+						default:
+						}
+					}
+				}
+				h.mu.RUnlock()
+				continue
+			}
 			for c := range h.rooms[rm.Room] {
 				select {
 				case c.Send <- rm.Msg:
@@ -77,8 +94,9 @@ func (h *Hub) Run() {
 
 const AdminRoom = "admin"
 
-func (h *Hub) BroadcastSeatUpdate(room string, payload interface{}) {
-	msg := Message{Type: "SEAT_UPDATE", Payload: payload}
+// BroadcastSeatUpdate sends changed seats to a screening room; version is the seat map version they belong to.
+func (h *Hub) BroadcastSeatUpdate(room string, version int64, payload interface{}) {
+	msg := Message{Type: "SEAT_UPDATE", Payload: payload, Version: version}
 	b, err := json.Marshal(msg)
 	if err != nil {
 		log.Printf("ws: marshal: %v", err)
@@ -127,5 +145,26 @@ func (h *Hub) BroadcastNotification(room string, eventType string, payload inter
 	}
 }
 
-func (h *Hub) Register(c *Client) { h.register <- c }
+// NotifyUser sends a private NOTIFICATION to every connection of one user, whatever room it joined.
+func (h *Hub) NotifyUser(userID string, eventType string, payload interface{}) {
+	msg := Message{
+		Type: "NOTIFICATION",
+		Payload: map[string]interface{}{
+			"eventType": eventType,
+			"payload":   payload,
+		},
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("ws: marshal user notification: %v", err)
+		return
+	}
+	select {
+	case h.broadcast <- roomMessage{UserID: userID, Msg: b}:
+	default:
+		log.Printf("ws: broadcast buffer full for user %s", userID)
+	}
+}
+
+func (h *Hub) Register(c *Client)   { h.register <- c }
 func (h *Hub) Unregister(c *Client) { h.unregister <- c }
@@ -1,13 +1,21 @@
 const base = import.meta.env.VITE_API_URL || ''
 
-function headers() {
+function headers(screeningId) {
   const token = localStorage.getItem('token')
+  const admission = screeningId ? sessionStorage.getItem(`admission:${screeningId}`) : null
   return {
     'Content-Type': 'application/json',
     ...(token ? { Authorization: `Bearer ${token}` } : {}),
+    ...(admission ? { 'X-Admission-Token': admission } : {}),
   }
 }
 
+/** เก็บ admission token ของห้องรอ (waiting room) ไว้แนบกับคำขอดูผังที่นั่ง/ล็อกที่นั่ง */
+export function setAdmissionToken(screeningId, token) {
+  if (token) sessionStorage.setItem(`admission:${screeningId}`, token)
+  else sessionStorage.removeItem(`admission:${screeningId}`)
+}
+
 export async function login(body) {
   const r = await fetch(`${base}/auth/login`, {
     method: 'POST',
@@ -51,6 +59,21 @@ export async function adminLogin(body) {
   return r.json()
 }
 
+/** ภาพยนตร์ที่กำลังฉาย พร้อมรอบฉายที่ยังไม่ปิดขาย — [{ movie, screenings }] */
+export async function nowShowing(cinemaId) {
+  const q = cinemaId ? `?cinema_id=${encodeURIComponent(cinemaId)}` : ''
+  const r = await fetch(`${base}/api/movies${q}`, { headers: headers() })
+  if (!r.ok) throw new Error('Failed to load movies')
+  return r.json()
+}
+
+/** ภาพยนตร์หนึ่งเรื่องพร้อมรอบฉายที่จะถึง */
+export async function getMovie(id) {
+  const r = await fetch(`${base}/api/movies/${id}`, { headers: headers() })
+  if (!r.ok) throw new Error('Movie not found')
+  return r.json()
+}
+
 export async function getScreenings() {
   const r = await fetch(`${base}/api/screenings`, { headers: headers() })
   if (!r.ok) throw new Error('Failed to load screenings')
@@ -64,7 +87,7 @@ export async function getScreening(id) {
 }
 
 export async function getSeatMap(id) {
-  const r = await fetch(`${base}/api/screenings/${id}/seats`, { headers: headers() })
+  const r = await fetch(`${base}/api/screenings/${id}/seats`, { headers: headers(id) })
   const data = await r.json().catch(() => ({}))
   if (!r.ok) {
     if (r.status === 401) throw new Error('Please log in again')
@@ -73,9 +96,18 @@ export async function getSeatMap(id) {
   return data
 }
 
+/** ที่นั่งที่เปลี่ยนหลังเวอร์ชัน since — คืน null ถ้าต้องโหลดผังใหม่ทั้งหมด (410) */
+export async function getSeatChanges(id, since) {
+  const r = await fetch(`${base}/api/screenings/${id}/seats/changes?since=${since}`, { headers: headers(id) })
+  const data = await r.json().catch(() => ({}))
+  if (r.status === 410) return null
+  if (!r.ok) throw new Error(data.error || 'Failed to load seat changes')
+  return data
+}
+
 /** รายการที่นั่งที่ถูกล็อก/จอง พร้อมเวลา (สำหรับหน้า ScreeningList) */
 export async function getSeatDetails(id) {
-  const r = await fetch(`${base}/api/screenings/${id}/seat-details`, { headers: headers() })
+  const r = await fetch(`${base}/api/screenings/${id}/seat-details`, { headers: headers(id) })
   const data = await r.json().catch(() => ({}))
   if (!r.ok) {
     if (r.status === 401) throw new Error('Please log in again')
@@ -87,7 +119,7 @@ export async function getSeatDetails(id) {
 export async function lockSeat(screeningId, row, col) {
   const r = await fetch(`${base}/api/screenings/${screeningId}/lock`, {
     method: 'POST',
-    headers: headers(),
+    headers: headers(screeningId),
     body: JSON.stringify({ row, col }),
   })
   const data = await r.json().catch(() => ({}))
@@ -95,6 +127,50 @@ export async function lockSeat(screeningId, row, col) {
   return data
 }
 
+/** ล็อกหลายที่นั่งพร้อมกัน (ได้ทั้งหมดหรือไม่ได้เลย) — seats: [{ row, col }] */
+export async function lockSeats(screeningId, seats) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/lock`, {
+    method: 'POST',
+    headers: headers(screeningId),
+    body: JSON.stringify({ seats }),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Lock failed')
+  return data
+}
+
+/** ล็อกที่นั่งตามป้ายชื่อ เช่น ['F7', 'F8'] — ป้ายที่ไม่รู้จัก → error code INVALID_SEAT_LABEL */
+export async function lockSeatsByLabel(screeningId, labels) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/lock`, {
+    method: 'POST',
+    headers: headers(screeningId),
+    body: JSON.stringify({ labels }),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Lock failed')
+  return data
+}
+
+/** หาบล็อกที่นั่งติดกันที่ดีที่สุดสำหรับจำนวนคน — prefer: center | back | front */
+export async function findBestSeats(screeningId, partySize, prefer = 'center') {
+  const q = new URLSearchParams({ party_size: partySize, prefer }).toString()
+  const r = await fetch(`${base}/api/screenings/${screeningId}/best-seats?${q}`, { headers: headers(screeningId) })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Failed to find seats')
+  return data
+}
+
+export async function lockBestSeats(screeningId, partySize, prefer = 'center') {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/best-seats/lock`, {
+    method: 'POST',
+    headers: headers(screeningId),
+    body: JSON.stringify({ party_size: partySize, prefer }),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Lock failed')
+  return data
+}
+
 export async function confirmPayment(bookingId) {
   const r = await fetch(`${base}/api/bookings/confirm`, {
     method: 'POST',
@@ -106,6 +182,162 @@ export async function confirmPayment(bookingId) {
   return data
 }
 
+/** ขยายเวลาล็อกของ booking ที่ยัง PENDING */
+export async function extendLock(bookingId) {
+  const r = await fetch(`${base}/api/bookings/${bookingId}/extend`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Extend failed')
+  return data
+}
+
+/** ยกเลิก booking — PENDING ปล่อยที่นั่งทันที */
+export async function cancelBooking(bookingId) {
+  const r = await fetch(`${base}/api/bookings/${bookingId}/cancel`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Cancel failed')
+  return data
+}
+
+/** ย้ายที่นั่งของ booking ที่ CONFIRMED แล้ว ไปที่นั่งว่างอื่นในรอบเดียวกัน (ส่ง screeningId เพื่อแนบ admission token ของห้องรอ) */
+export async function exchangeSeat(bookingId, row, col, screeningId) {
+  const r = await fetch(`${base}/api/bookings/${bookingId}/exchange`, {
+    method: 'POST',
+    headers: headers(screeningId),
+    body: JSON.stringify({ row, col }),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Exchange failed')
+  return data
+}
+
+/** เสนอโอนตั๋ว (booking CONFIRMED) ให้ผู้ใช้อื่นตามอีเมล */
+export async function offerTransfer(bookingId, email) {
+  const r = await fetch(`${base}/api/bookings/${bookingId}/transfer`, {
+    method: 'POST',
+    headers: headers(),
+    body: JSON.stringify({ email }),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Transfer failed')
+  return data
+}
+
+/** ถอนข้อเสนอโอนตั๋วที่ยังไม่มีผู้รับ */
+export async function revokeTransfer(bookingId) {
+  const r = await fetch(`${base}/api/bookings/${bookingId}/transfer`, {
+    method: 'DELETE',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Revoke failed')
+  return data
+}
+
+/** ข้อเสนอโอนตั๋วที่ส่งมาถึงเรา และยังไม่หมดอายุ */
+export async function getIncomingTransfers() {
+  const r = await fetch(`${base}/api/me/transfers`, { headers: headers() })
+  const data = await r.json().catch(() => ([]))
+  if (!r.ok) throw new Error(data.error || 'Failed to load transfers')
+  return data
+}
+
+export async function acceptTransfer(bookingId) {
+  const r = await fetch(`${base}/api/bookings/${bookingId}/transfer/accept`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Accept failed')
+  return data
+}
+
+/** ดึง order พร้อม booking ทุกที่นั่งในตะกร้า */
+export async function getOrder(orderId) {
+  const r = await fetch(`${base}/api/orders/${orderId}`, { headers: headers() })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Failed to load order')
+  return data
+}
+
+/** ชำระเงินทุกที่นั่งใน order ครั้งเดียว */
+export async function confirmOrder(orderId) {
+  const r = await fetch(`${base}/api/orders/${orderId}/confirm`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Payment failed')
+  return data
+}
+
+export async function cancelOrder(orderId) {
+  const r = await fetch(`${base}/api/orders/${orderId}/cancel`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Cancel failed')
+  return data
+}
+
+/** เข้าห้องรอของรอบที่เปิด waiting room — คืน { state, position, admission_token } */
+export async function joinQueue(screeningId) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/queue`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Join queue failed')
+  setAdmissionToken(screeningId, data.admission_token)
+  return data
+}
+
+export async function queueStatus(screeningId) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/queue`, { headers: headers() })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Failed to load queue')
+  setAdmissionToken(screeningId, data.admission_token)
+  return data
+}
+
+export async function leaveQueue(screeningId) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/queue`, {
+    method: 'DELETE',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Leave queue failed')
+  setAdmissionToken(screeningId, null)
+  return data
+}
+
+/** เข้าคิวรอที่นั่งของรอบที่เต็มแล้ว — คืน { position } */
+export async function joinWaitlist(screeningId) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/waitlist`, {
+    method: 'POST',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Join waitlist failed')
+  return data
+}
+
+export async function leaveWaitlist(screeningId) {
+  const r = await fetch(`${base}/api/screenings/${screeningId}/waitlist`, {
+    method: 'DELETE',
+    headers: headers(),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Leave waitlist failed')
+  return data
+}
+
 export function wsUrl(screeningId) {
   const token = localStorage.getItem('token')
   const host = (import.meta.env.VITE_WS_URL || base || window.location.origin).replace(/^http/, 'ws')
@@ -121,6 +353,15 @@ export function wsAdminUrl() {
   return token ? `${path}?token=${encodeURIComponent(token)}` : path
 }
 
+/** booking ของฉัน — params: { tab: 'upcoming' | 'past', status, page, page_size } */
+export async function myBookings(params = {}) {
+  const q = new URLSearchParams(params).toString()
+  const r = await fetch(`${base}/api/me/bookings?${q}`, { headers: headers() })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Failed to load bookings')
+  return data
+}
+
 // Admin
 export async function adminBookings(params = {}) {
   const q = new URLSearchParams(params).toString()
@@ -145,3 +386,109 @@ export async function createScreening(body) {
   if (!r.ok) throw new Error(data.error || 'Create failed')
   return data
 }
+
+/** ภาพยนตร์ทั้งหมดในแคตตาล็อก */
+export async function adminMovies() {
+  const r = await fetch(`${base}/admin/movies`, { headers: headers() })
+  if (!r.ok) throw new Error('Failed to load movies')
+  return r.json()
+}
+
+/** เพิ่มภาพยนตร์ — body: { title, runtime_minutes, age_rating, genres, languages, poster_url, release_date, end_date } */
+export async function createMovie(body) {
+  const r = await fetch(`${base}/admin/movies`, {
+    method: 'POST',
+    headers: headers(),
+    body: JSON.stringify(body),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Create movie failed')
+  return data
+}
+
+/** แก้ไขภาพยนตร์ (ชื่อใหม่จะถูกคัดลอกไปยังรอบฉายทั้งหมด) */
+export async function updateMovie(id, body) {
+  const r = await fetch(`${base}/admin/movies/${id}`, {
+    method: 'PUT',
+    headers: headers(),
+    body: JSON.stringify(body),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Update movie failed')
+  return data
+}
+
+/** ผังโรง (layout) ทั้งหมด — ไม่รวม cells */
+export async function adminLayouts() {
+  const r = await fetch(`${base}/admin/layouts`, { headers: headers() })
+  if (!r.ok) throw new Error('Failed to load layouts')
+  return r.json()
+}
+
+/** สร้างผังโรง — body: { name, map: ['SS_SS', ...] } หรือ { name, rows, cols, cells } */
+export async function createLayout(body) {
+  const r = await fetch(`${base}/admin/layouts`, {
+    method: 'POST',
+    headers: headers(),
+    body: JSON.stringify(body),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Create layout failed')
+  return data
+}
+
+/** สาขาโรงภาพยนตร์ทั้งหมด */
+export async function adminCinemas() {
+  const r = await fetch(`${base}/admin/cinemas`, { headers: headers() })
+  if (!r.ok) throw new Error('Failed to load cinemas')
+  return r.json()
+}
+
+/** สร้างสาขา — body: { name, city, address } */
+export async function createCinema(body) {
+  const r = await fetch(`${base}/admin/cinemas`, {
+    method: 'POST',
+    headers: headers(),
+    body: JSON.stringify(body),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Create cinema failed')
+  return data
+}
+
+/** โรงฉายทั้งหมด (กรองด้วย cinemaId ได้) */
+export async function adminHalls(cinemaId) {
+  const q = cinemaId ? `?cinema_id=${encodeURIComponent(cinemaId)}` : ''
+  const r = await fetch(`${base}/admin/halls${q}`, { headers: headers() })
+  if (!r.ok) throw new Error('Failed to load halls')
+  return r.json()
+}
+
+/** สร้างโรงฉาย — body: { cinema_id, name, layout_id, cleaning_minutes } */
+export async function createHall(body) {
+  const r = await fetch(`${base}/admin/halls`, {
+    method: 'POST',
+    headers: headers(),
+    body: JSON.stringify(body),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) throw new Error(data.error || 'Create hall failed')
+  return data
+}
+
+/** เลื่อนรอบฉาย — body: { screen_at, runtime_minutes, hall_id }; ชนกับรอบอื่นในโรงเดียวกัน → error code SCHEDULE_CONFLICT */
+export async function updateScreening(id, body) {
+  const r = await fetch(`${base}/admin/screenings/${id}`, {
+    method: 'PUT',
+    headers: headers(),
+    body: JSON.stringify(body),
+  })
+  const data = await r.json().catch(() => ({}))
+  if (!r.ok) {
+    const err = new Error(data.error || 'Update failed')
+    err.code = data.code
+    err.conflict = data.conflict
+    throw err
+  }
+  return data
+}